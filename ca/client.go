@@ -18,27 +18,41 @@ type Client struct {
 // The service will validate the CSR and respond with a request ID.
 //
 // The request ID can be used to get the status of the request using the GetCSRStatus method.
-func (c *Client) PostCSR(ctx context.Context, request PostCSRRequest) (PostCSRResponse, error) {
-	path := "/csr"
-	resp := new(PostCSRResponse)
+func (c *Client) PostCSR(ctx context.Context, request PostCSRRequest, opts ...rest.CallOption) (PostCSRResponse, error) {
+	ctx = rest.WithOperation(ctx, "PostCSR")
 
-	if err := c.Do(ctx, http.MethodPost, path, emptyHeaders, &request, resp); err != nil {
-		return *resp, fmt.Errorf("failed to post CSR: %w", err)
+	resp, err := rest.Do[PostCSRResponse](ctx, c.Client, rest.Request{
+		Method:  http.MethodPost,
+		Path:    "/csr",
+		Query:   nil,
+		Headers: emptyHeaders,
+		Payload: &request,
+		Options: opts,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to post CSR: %w", err)
 	}
 
-	return *resp, nil
+	return resp, nil
 }
 
 // GetCSRStatus retrieves the status of a Certificate Signing Request (CSR) from the Certificate Authority (CA) service.
-func (c *Client) GetCSRStatus(ctx context.Context, requestID string) (GetCSRStatusResponse, error) {
-	path := "/csr/" + url.PathEscape(requestID)
-	resp := new(GetCSRStatusResponse)
+func (c *Client) GetCSRStatus(ctx context.Context, requestID string, opts ...rest.CallOption) (GetCSRStatusResponse, error) {
+	ctx = rest.WithOperation(ctx, "GetCSRStatus")
 
-	if err := c.Do(ctx, http.MethodGet, path, emptyHeaders, nil, resp); err != nil {
-		return *resp, fmt.Errorf("failed to get CSR status: %w", err)
+	resp, err := rest.Do[GetCSRStatusResponse](ctx, c.Client, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/csr/" + url.PathEscape(requestID),
+		Query:   nil,
+		Headers: emptyHeaders,
+		Payload: nil,
+		Options: opts,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get CSR status: %w", err)
 	}
 
-	return *resp, nil
+	return resp, nil
 }
 
 // NewClient creates a new instance of the CA API Client.
@@ -48,10 +62,14 @@ func NewClient(options ...Option) *Client {
 		option(config)
 	}
 
+	restConfig := config.rest
+	restConfig.Client = config.Client()
+	restConfig.BaseURL = config.BaseURL()
+	restConfig.Retry = config.RetryPolicy()
+	// CSR statuses must not be stale
+	restConfig.Cache = nil
+
 	return &Client{
-		Client: rest.NewClient(rest.Config{
-			Client:  config.Client(),
-			BaseURL: config.BaseURL(),
-		}),
+		Client: rest.NewClient(restConfig),
 	}
 }
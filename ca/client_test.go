@@ -6,9 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/android-sms-gateway/client-go/ca"
+	"github.com/android-sms-gateway/client-go/rest"
 )
 
 func TestClient_PostCSR(t *testing.T) {
@@ -140,3 +143,76 @@ func TestClient_GetCSRStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Middleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"request_id":"123","status":"pending"}`))
+	}))
+	defer server.Close()
+
+	var operations []string
+	client := ca.NewClient(
+		ca.WithBaseURL(server.URL),
+		ca.WithMiddleware(func(next rest.RoundTripFunc) rest.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				operations = append(operations, rest.OperationFromContext(req.Context()))
+				return next(req)
+			}
+		}),
+	)
+
+	if _, err := client.PostCSR(context.Background(), ca.PostCSRRequest{}); err != nil {
+		t.Fatalf("Client.PostCSR() error = %v", err)
+	}
+	if _, err := client.GetCSRStatus(context.Background(), "123"); err != nil {
+		t.Fatalf("Client.GetCSRStatus() error = %v", err)
+	}
+
+	if want := []string{"PostCSR", "GetCSRStatus"}; !reflect.DeepEqual(operations, want) {
+		t.Errorf("operations = %v, want %v", operations, want)
+	}
+}
+
+func TestClient_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"request_id":"123","status":"pending"}`))
+	}))
+	defer server.Close()
+
+	// the default client is created from the transport settings
+	client := ca.NewClient(
+		ca.WithBaseURL(server.URL),
+		ca.WithRetryPolicy(rest.NoRetry()),
+		ca.WithTransport(rest.TransportConfig{Timeout: 20 * time.Millisecond}),
+	)
+
+	if _, err := client.GetCSRStatus(context.Background(), "123"); err == nil {
+		t.Error("Client.GetCSRStatus() error = nil, want timeout")
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"request_id":"123","status":"pending"}`))
+	}))
+	defer server.Close()
+
+	// CSR statuses must not be stale
+	client := ca.NewClient(
+		ca.WithBaseURL(server.URL),
+		ca.WithOptions(rest.WithCache(rest.NewCache(rest.CacheConfig{TTL: time.Hour}))),
+	)
+
+	for range 2 {
+		if _, err := client.GetCSRStatus(context.Background(), "123"); err != nil {
+			t.Fatalf("Client.GetCSRStatus() error = %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
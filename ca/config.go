@@ -1,36 +1,125 @@
 package ca
 
-import "net/http"
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
 
 type Option func(*Config)
 
+// Config holds the settings of the CA client, set with options.
+//
+// Unset settings default to a client created with `Transport`, the
+// `https://ca.sms-gate.app/api/v1` base URL and `rest.DefaultRetryPolicy()`.
 type Config struct {
-	client  *http.Client // Optional HTTP Client, defaults to `http.DefaultClient`
-	baseURL string       // Optional base URL, defaults to `https://ca.sms-gate.app/api/v1`
+	rest rest.Config
+
+	client *http.Client // default client tuned with `Transport`
 }
 
+//nolint:gochecknoglobals // shared by configs without transport settings
+var defaultClient = sync.OnceValue(func() *http.Client {
+	return rest.NewHTTPClient(rest.TransportConfig{}) //nolint:exhaustruct // defaults
+})
+
+// Client returns the HTTP client set with WithClient or the default client,
+// which is created once when the transport settings change.
 func (c Config) Client() *http.Client {
-	if c.client == nil {
-		return http.DefaultClient
+	if c.rest.Client != nil {
+		return c.rest.Client
 	}
-	return c.client
+	if c.client != nil {
+		return c.client
+	}
+	return defaultClient()
+}
+
+func (c Config) Transport() rest.TransportConfig {
+	return c.rest.Transport
 }
 
 func (c Config) BaseURL() string {
-	if c.baseURL == "" {
+	if c.rest.BaseURL == "" {
 		return BASE_URL
 	}
-	return c.baseURL
+	return c.rest.BaseURL
 }
 
-func WithClient(client *http.Client) Option {
-	return func(c *Config) {
-		c.client = client
+func (c Config) RetryPolicy() *rest.RetryPolicy {
+	if c.rest.Retry == nil {
+		return rest.DefaultRetryPolicy()
 	}
+	return c.rest.Retry
 }
 
-func WithBaseURL(baseURL string) Option {
+func (c Config) CircuitBreaker() *rest.CircuitBreaker {
+	return c.rest.CircuitBreaker
+}
+
+func (c Config) TLS() rest.TLSConfig {
+	return c.rest.TLS
+}
+
+func (c Config) Middlewares() []rest.Middleware {
+	return c.rest.Middlewares
+}
+
+func (c Config) Logger() *slog.Logger {
+	return c.rest.Logger
+}
+
+// WithOptions applies options shared with the other API clients, such as
+// rest.WithCompression or rest.WithLogBodies. The CA client never caches
+// responses, as CSR statuses must not be stale, so rest.WithCache is ignored.
+func WithOptions(options ...rest.Option) Option {
 	return func(c *Config) {
-		c.baseURL = baseURL
+		transport := c.rest.Transport
+		c.rest.Apply(options...)
+		if c.rest.Transport != transport {
+			c.client = rest.NewHTTPClient(c.rest.Transport)
+		}
 	}
 }
+
+func WithClient(client *http.Client) Option {
+	return WithOptions(rest.WithClient(client))
+}
+
+// WithTransport tunes timeouts and connection pooling of the default HTTP
+// client. It is ignored if a client is set with WithClient.
+func WithTransport(config rest.TransportConfig) Option {
+	return WithOptions(rest.WithTransport(config))
+}
+
+func WithBaseURL(baseURL string) Option {
+	return WithOptions(rest.WithBaseURL(baseURL))
+}
+
+func WithRetryPolicy(policy *rest.RetryPolicy) Option {
+	return WithOptions(rest.WithRetryPolicy(policy))
+}
+
+// WithMiddleware appends middlewares to the chain around every HTTP request.
+func WithMiddleware(middlewares ...rest.Middleware) Option {
+	return WithOptions(rest.WithMiddleware(middlewares...))
+}
+
+// WithLogger enables logging of requests at debug level, retries at warn level
+// and failures at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return WithOptions(rest.WithLogger(logger))
+}
+
+// WithCircuitBreaker stops sending requests to the CA service while it is failing.
+func WithCircuitBreaker(breaker *rest.CircuitBreaker) Option {
+	return WithOptions(rest.WithCircuitBreaker(breaker))
+}
+
+// WithTLS trusts additional root CAs, pins server keys and sets client
+// certificates for mutual TLS.
+func WithTLS(config rest.TLSConfig) Option {
+	return WithOptions(rest.WithTLS(config))
+}
@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/android-sms-gateway/client-go/ca"
+	"github.com/android-sms-gateway/client-go/rest"
 )
 
 //nolint:gochecknoglobals // constant
@@ -13,28 +15,56 @@ var customHTTPClient = &http.Client{}
 
 func TestConfig_Client(t *testing.T) {
 	tests := []struct {
-		name   string
-		option ca.Option
-		want   *http.Client
+		name        string
+		options     []ca.Option
+		want        *http.Client
+		wantTimeout time.Duration
 	}{
 		{
-			name:   "With Client",
-			option: ca.WithClient(customHTTPClient),
-			want:   customHTTPClient,
+			name:    "With Client",
+			options: []ca.Option{ca.WithClient(customHTTPClient)},
+			want:    customHTTPClient,
+		},
+		{
+			name:        "Without Client",
+			options:     []ca.Option{ca.WithClient(nil)},
+			wantTimeout: rest.DefaultTransportConfig().Timeout,
 		},
 		{
-			name:   "Without Client",
-			option: ca.WithClient(nil),
-			want:   http.DefaultClient,
+			name:        "With Transport",
+			options:     []ca.Option{ca.WithTransport(rest.TransportConfig{Timeout: time.Minute})},
+			wantTimeout: time.Minute,
+		},
+		{
+			name:    "With Client and Transport",
+			options: []ca.Option{ca.WithClient(customHTTPClient), ca.WithTransport(rest.TransportConfig{Timeout: time.Minute})},
+			want:    customHTTPClient,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := ca.Config{}
-			tt.option(&c)
+			for _, option := range tt.options {
+				option(&c)
+			}
+
+			got := c.Client()
+			if again := c.Client(); again != got {
+				t.Errorf("Config.Client() = %p, then %p, want the same client", got, again)
+			}
+			if tt.want != nil {
+				if got != tt.want {
+					t.Errorf("Config.Client() = %v, want %v", got, tt.want)
+				}
+				return
+			}
 
-			if got := c.Client(); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Config.Client() = %v, want %v", got, tt.want)
+			// the default client is tuned instead of http.DefaultClient
+			if got == http.DefaultClient || got.Timeout != tt.wantTimeout {
+				t.Errorf("Config.Client() = %v, want default client with timeout %v", got, tt.wantTimeout)
+			}
+			if _, ok := got.Transport.(*http.Transport); !ok {
+				t.Errorf("Config.Client().Transport = %T, want *http.Transport", got.Transport)
 			}
 		})
 	}
@@ -68,3 +98,78 @@ func TestConfig_BaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_RetryPolicy(t *testing.T) {
+	customPolicy := rest.NoRetry()
+
+	tests := []struct {
+		name   string
+		option ca.Option
+		want   *rest.RetryPolicy
+	}{
+		{
+			name:   "With Retry Policy",
+			option: ca.WithRetryPolicy(customPolicy),
+			want:   customPolicy,
+		},
+		{
+			name:   "Without Retry Policy",
+			option: ca.WithRetryPolicy(nil),
+			want:   rest.DefaultRetryPolicy(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ca.Config{}
+			tt.option(&c)
+			if got := c.RetryPolicy(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Config.RetryPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_TLS(t *testing.T) {
+	pins := rest.TLSConfig{Pins: []string{"sha256/AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}}
+
+	tests := []struct {
+		name   string
+		option ca.Option
+		want   rest.TLSConfig
+	}{
+		{
+			name:   "With TLS",
+			option: ca.WithTLS(pins),
+			want:   pins,
+		},
+		{
+			name:   "Without TLS",
+			option: ca.WithTLS(rest.TLSConfig{}),
+			want:   rest.TLSConfig{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ca.Config{}
+			tt.option(&c)
+			if got := c.TLS(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Config.TLS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	policy := rest.NoRetry()
+
+	c := ca.Config{}
+	ca.WithOptions(rest.WithBaseURL("https://example.com"), rest.WithRetryPolicy(policy))(&c)
+	ca.WithLogger(nil)(&c)
+
+	if got := c.BaseURL(); got != "https://example.com" {
+		t.Errorf("Config.BaseURL() = %v, want %v", got, "https://example.com")
+	}
+	if got := c.RetryPolicy(); got != policy {
+		t.Errorf("Config.RetryPolicy() = %v, want %v", got, policy)
+	}
+}
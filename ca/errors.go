@@ -1,7 +1,36 @@
 package ca
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
 
 var (
 	ErrValidationFailed = errors.New("validation failed")
 )
+
+// IsBadRequest reports whether err is caused by a 400 Bad Request response.
+func IsBadRequest(err error) bool {
+	return rest.IsBadRequest(err)
+}
+
+// IsUnauthorized reports whether err is caused by a 401 Unauthorized response.
+func IsUnauthorized(err error) bool {
+	return rest.IsUnauthorized(err)
+}
+
+// IsNotFound reports whether err is caused by a 404 Not Found response.
+func IsNotFound(err error) bool {
+	return rest.IsNotFound(err)
+}
+
+// IsRateLimited reports whether err is caused by a 429 Too Many Requests response.
+func IsRateLimited(err error) bool {
+	return rest.IsRateLimited(err)
+}
+
+// IsServerError reports whether err is caused by a 5xx response.
+func IsServerError(err error) bool {
+	return rest.IsServerError(err)
+}
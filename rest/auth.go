@@ -0,0 +1,182 @@
+package rest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultRefreshBefore = 30 * time.Second
+
+var ErrNoToken = errors.New("no access token")
+
+// Authenticator authorizes outgoing requests. It is invoked for every attempt.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Refresher is implemented by authenticators whose credentials can be
+// refreshed after a 401 Unauthorized response. The request is retried once
+// if Refresh succeeds.
+type Refresher interface {
+	// Refresh renews the credentials used for the rejected request.
+	Refresh(ctx context.Context, rejected *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface.
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BasicAuth returns an authenticator that uses HTTP Basic authentication.
+func BasicAuth(user, password string) Authenticator {
+	header := "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", header)
+		return nil
+	})
+}
+
+// BearerToken returns an authenticator that uses a static bearer token.
+func BearerToken(token string) Authenticator {
+	header := "Bearer " + token
+
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", header)
+		return nil
+	})
+}
+
+// Token is a short-lived access token.
+type Token struct {
+	AccessToken string    // Bearer token
+	ExpiresAt   time.Time // Optional expiration time, read from the `exp` claim of JWTs if not set
+}
+
+// TokenFetcher obtains a new access token.
+type TokenFetcher func(ctx context.Context) (Token, error)
+
+// TokenSource is an authenticator that caches bearer tokens obtained from a
+// TokenFetcher. Tokens are refreshed shortly before they expire and after a
+// 401 Unauthorized response. It is safe for concurrent use.
+type TokenSource struct {
+	fetch         TokenFetcher
+	refreshBefore time.Duration
+
+	mu    sync.Mutex
+	token Token
+}
+
+// NewTokenSource creates a TokenSource that refreshes tokens refreshBefore
+// their expiration, defaults to 30 seconds.
+func NewTokenSource(fetch TokenFetcher, refreshBefore time.Duration) *TokenSource {
+	if refreshBefore <= 0 {
+		refreshBefore = defaultRefreshBefore
+	}
+
+	return &TokenSource{
+		fetch:         fetch,
+		refreshBefore: refreshBefore,
+
+		mu:    sync.Mutex{},
+		token: Token{AccessToken: "", ExpiresAt: time.Time{}},
+	}
+}
+
+// Token returns a valid token, fetching a new one if needed.
+func (s *TokenSource) Token(ctx context.Context) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.valid() {
+		return s.token, nil
+	}
+
+	return s.refresh(ctx)
+}
+
+// Authenticate sets the bearer token of the request.
+func (s *TokenSource) Authenticate(req *http.Request) error {
+	token, err := s.Token(req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	return nil
+}
+
+// Refresh fetches a new token unless the rejected request used an outdated
+// one that has already been replaced.
+func (s *TokenSource) Refresh(ctx context.Context, rejected *http.Request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rejected != nil && rejected.Header.Get("Authorization") != "Bearer "+s.token.AccessToken && s.valid() {
+		return nil
+	}
+
+	_, err := s.refresh(ctx)
+	return err
+}
+
+// valid reports whether the cached token can be used. Must be called with mu held.
+func (s *TokenSource) valid() bool {
+	if s.token.AccessToken == "" {
+		return false
+	}
+
+	return s.token.ExpiresAt.IsZero() || time.Now().Add(s.refreshBefore).Before(s.token.ExpiresAt)
+}
+
+// refresh fetches a new token. Must be called with mu held.
+func (s *TokenSource) refresh(ctx context.Context) (Token, error) {
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return Token{}, fmt.Errorf("failed to fetch token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return Token{}, ErrNoToken
+	}
+
+	if token.ExpiresAt.IsZero() {
+		token.ExpiresAt = jwtExpiration(token.AccessToken)
+	}
+
+	s.token = token
+
+	return token, nil
+}
+
+// jwtExpiration returns the `exp` claim of a JWT, or zero time if the token
+// isn't a JWT or has no expiration.
+func jwtExpiration(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:mnd // header, payload and signature
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,168 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestAuthenticators(t *testing.T) {
+	tests := []struct {
+		name string
+		auth rest.Authenticator
+		want string
+	}{
+		{
+			name: "Basic",
+			auth: rest.BasicAuth("user", "pass"),
+			want: "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pass")),
+		},
+		{
+			name: "Bearer",
+			auth: rest.BearerToken("token"),
+			want: "Bearer token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if err := tt.auth.Authenticate(req); err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func jwt(exp time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+
+	return header + "." + payload + ".sig"
+}
+
+func TestTokenSource_Token(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     func() rest.Token
+		wantFetch int32
+	}{
+		{
+			name:      "Without expiration",
+			token:     func() rest.Token { return rest.Token{AccessToken: "token"} },
+			wantFetch: 1,
+		},
+		{
+			name:      "Valid",
+			token:     func() rest.Token { return rest.Token{AccessToken: "token", ExpiresAt: time.Now().Add(time.Hour)} },
+			wantFetch: 1,
+		},
+		{
+			name:      "Expiring",
+			token:     func() rest.Token { return rest.Token{AccessToken: "token", ExpiresAt: time.Now().Add(time.Second)} },
+			wantFetch: 2,
+		},
+		{
+			name:      "Valid JWT",
+			token:     func() rest.Token { return rest.Token{AccessToken: jwt(time.Now().Add(time.Hour))} },
+			wantFetch: 1,
+		},
+		{
+			name:      "Expired JWT",
+			token:     func() rest.Token { return rest.Token{AccessToken: jwt(time.Now().Add(-time.Hour))} },
+			wantFetch: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches atomic.Int32
+			source := rest.NewTokenSource(func(context.Context) (rest.Token, error) {
+				fetches.Add(1)
+				return tt.token(), nil
+			}, 0)
+
+			for range 2 {
+				if _, err := source.Token(context.Background()); err != nil {
+					t.Fatalf("Token() error = %v", err)
+				}
+			}
+
+			if got := fetches.Load(); got != tt.wantFetch {
+				t.Errorf("fetches = %d, want %d", got, tt.wantFetch)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RefreshOnUnauthorized(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var fetches atomic.Int32
+	source := rest.NewTokenSource(func(context.Context) (rest.Token, error) {
+		n := fetches.Add(1)
+		return rest.Token{AccessToken: fmt.Sprintf("token-%d", n)}, nil
+	}, 0)
+
+	client := rest.NewClient(rest.Config{
+		BaseURL:       server.URL,
+		Retry:         rest.NoRetry(),
+		Authenticator: source,
+	})
+
+	if err := client.Do(context.Background(), http.MethodPost, "/", nil, nil, nil); err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+
+}
+
+func TestClient_Do_RefreshOnce(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	source := rest.NewTokenSource(func(context.Context) (rest.Token, error) {
+		return rest.Token{AccessToken: "token"}, nil
+	}, 0)
+
+	client := rest.NewClient(rest.Config{
+		BaseURL:       server.URL,
+		Authenticator: source,
+	})
+
+	err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+	if !rest.IsUnauthorized(err) {
+		t.Fatalf("Client.Do() error = %v, want unauthorized", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
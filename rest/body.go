@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBufferSize limits the capacity of buffers returned to the pool,
+// so a single large payload doesn't stay in memory.
+const maxPooledBufferSize = 64 << 10
+
+//nolint:gochecknoglobals // shared pool
+var bodyPool = sync.Pool{
+	New: func() any {
+		body := new(requestBody)
+		body.enc = json.NewEncoder(&body.buf)
+
+		return body
+	},
+}
+
+// requestBody is an encoded request payload backed by pooled buffers.
+//
+// The body is returned to the pool once the owner has released it and every
+// reader handed to the transport has been closed.
+type requestBody struct {
+	raw      []byte // JSON payload
+	data     []byte // JSON payload encoded according to encoding
+	encoding string // Content-Encoding, empty for identity
+
+	buf  bytes.Buffer  // backs raw
+	zbuf bytes.Buffer  // backs data if compressed
+	enc  *json.Encoder // writes to buf
+	refs atomic.Int32
+}
+
+// encodeBody encodes the payload as JSON into a pooled buffer.
+func encodeBody(payload any) (*requestBody, error) {
+	body, _ := bodyPool.Get().(*requestBody)
+	body.buf.Reset()
+	body.zbuf.Reset()
+	body.encoding = ""
+	body.refs.Store(1)
+
+	if err := body.enc.Encode(payload); err != nil {
+		// not returned to the pool, the encoder may keep the error
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	// same output as json.Marshal
+	body.raw = bytes.TrimSuffix(body.buf.Bytes(), []byte("\n"))
+	body.data = body.raw
+
+	return body, nil
+}
+
+// reader returns a reader of the encoded payload that must be closed.
+func (b *requestBody) reader() io.ReadCloser {
+	b.refs.Add(1)
+
+	r := &bodyReader{
+		Reader: bytes.Reader{},
+		body:   b,
+		closed: atomic.Bool{},
+	}
+	r.Reset(b.data)
+
+	return r
+}
+
+// release drops a reference to the body.
+func (b *requestBody) release() {
+	if b.refs.Add(-1) > 0 {
+		return
+	}
+
+	b.raw, b.data = nil, nil
+	if b.buf.Cap() > maxPooledBufferSize || b.zbuf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bodyPool.Put(b)
+}
+
+type bodyReader struct {
+	bytes.Reader
+
+	body   *requestBody
+	closed atomic.Bool
+}
+
+func (r *bodyReader) Close() error {
+	if r.closed.CompareAndSwap(false, true) {
+		r.body.release()
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package rest_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestClient_Do_Body(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			// requires GetBody to resend the body
+			http.Redirect(w, r, "/target", http.StatusTemporaryRedirect)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if r.ContentLength != int64(len(body)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, _ = io.ReadAll(zr)
+		}
+
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		payload     any
+		compression rest.Compression
+		want        string
+	}{
+		{
+			name:    "Payload",
+			path:    "/target",
+			payload: map[string]string{"message": "<b>Hello</b>"},
+			want:    `{"message":"\u003cb\u003eHello\u003c/b\u003e"}`, // escaped like json.Marshal
+		},
+		{
+			name:    "Redirect",
+			path:    "/redirect",
+			payload: map[string]string{"message": "Hello"},
+			want:    `{"message":"Hello"}`,
+		},
+		{
+			name:        "Compressed redirect",
+			path:        "/redirect",
+			payload:     map[string]string{"message": strings.Repeat("a", 100)},
+			compression: rest.Compression{RequestThreshold: 10},
+			want:        `{"message":"` + strings.Repeat("a", 100) + `"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies = nil
+
+			client := rest.NewClient(rest.Config{
+				BaseURL:     server.URL,
+				Compression: tt.compression,
+			})
+
+			// pooled buffers are reused between requests
+			for range 3 {
+				err := client.DoRequest(context.Background(), rest.Request{
+					Method:  http.MethodPost,
+					Path:    tt.path,
+					Payload: tt.payload,
+				}, nil)
+				if err != nil {
+					t.Fatalf("DoRequest() error = %v", err)
+				}
+			}
+
+			if len(bodies) != 3 {
+				t.Fatalf("server received %d bodies, want 3", len(bodies))
+			}
+			for _, body := range bodies {
+				if body != tt.want {
+					t.Errorf("request body = %s, want %s", body, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultCoolDown         = 30 * time.Second
+	defaultHalfOpenRequests = 1
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests are allowed
+	CircuitOpen                         // Requests fail immediately with ErrCircuitOpen
+	CircuitHalfOpen                     // A limited number of trial requests are allowed
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // Consecutive failures that open the circuit, defaults to 5
+	CoolDown         time.Duration // Time the circuit stays open before trial requests are allowed, defaults to 30s
+	HalfOpenRequests int           // Successful trial requests required to close the circuit, defaults to 1
+
+	OnStateChange func(from, to CircuitState) // Optional callback for state changes
+}
+
+// CircuitBreaker stops sending requests to a failing server.
+//
+// Transport errors and 5xx responses are counted as failures. After
+// FailureThreshold consecutive failures the circuit opens and requests fail
+// with ErrCircuitOpen until CoolDown passes. Then the circuit becomes half-open
+// and lets HalfOpenRequests trial requests through: the circuit closes if all
+// of them succeed and opens again on the first failure.
+//
+// It is safe for concurrent use and can be shared between clients talking to
+// the same server.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu         sync.Mutex
+	state      CircuitState
+	generation uint64 // incremented on every state change
+	failures   int
+	successes  int
+	trials     int
+	openedAt   time.Time
+}
+
+// permit is issued by allow for a request that may be sent.
+type permit struct {
+	generation uint64 // state generation the request was admitted in
+	trial      bool   // admitted as a half-open trial request
+}
+
+// NewCircuitBreaker creates a new closed CircuitBreaker.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = defaultFailureThreshold
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = defaultCoolDown
+	}
+	if config.HalfOpenRequests < 1 {
+		config.HalfOpenRequests = defaultHalfOpenRequests
+	}
+
+	return &CircuitBreaker{
+		config: config,
+
+		mu:         sync.Mutex{},
+		state:      CircuitClosed,
+		generation: 0,
+		failures:   0,
+		successes:  0,
+		trials:     0,
+		openedAt:   time.Time{},
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.CoolDown {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// allow reports whether a request may be sent and returns the permit to
+// record its outcome with. A nil breaker allows everything.
+func (b *CircuitBreaker) allow() (permit, error) {
+	if b == nil {
+		return permit{generation: 0, trial: false}, nil
+	}
+
+	b.mu.Lock()
+	from := b.state
+	p, err := b.allowLocked()
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+
+	return p, err
+}
+
+func (b *CircuitBreaker) allowLocked() (permit, error) {
+	if b.state == CircuitOpen {
+		if time.Since(b.openedAt) < b.config.CoolDown {
+			return permit{generation: b.generation, trial: false}, ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+	}
+
+	if b.state == CircuitClosed {
+		return permit{generation: b.generation, trial: false}, nil
+	}
+
+	if b.trials >= b.config.HalfOpenRequests {
+		return permit{generation: b.generation, trial: false}, ErrCircuitOpen
+	}
+	b.trials++
+
+	return permit{generation: b.generation, trial: true}, nil
+}
+
+// record updates the circuit with the outcome of a request sent with the permit.
+// Outcomes of requests admitted before the last state change are ignored.
+func (b *CircuitBreaker) record(ctx context.Context, p permit, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if p.generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	from := b.state
+
+	if p.trial {
+		b.trials--
+	}
+
+	switch {
+	case isBreakerFailure(err):
+		b.successes = 0
+		b.failures++
+		if p.trial || b.failures >= b.config.FailureThreshold {
+			b.setState(CircuitOpen)
+		}
+	case err != nil && ctx.Err() != nil:
+		// canceled requests say nothing about the server
+	default:
+		b.failures = 0
+		if p.trial {
+			b.successes++
+			if b.successes >= b.config.HalfOpenRequests {
+				b.setState(CircuitClosed)
+			}
+		}
+	}
+
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// setState switches the state and resets the counters. Must be called with mu held.
+func (b *CircuitBreaker) setState(state CircuitState) {
+	b.state = state
+	b.generation++
+	b.failures = 0
+	b.successes = 0
+	b.trials = 0
+	if state == CircuitOpen {
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(from, to)
+	}
+}
+
+// isBreakerFailure reports whether err indicates an unhealthy server.
+func isBreakerFailure(err error) bool {
+	var delivered *deliveredError
+	if err == nil || errors.As(err, &delivered) {
+		// the server has responded
+		return false
+	}
+
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,188 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var transitions []string
+	breaker := rest.NewCircuitBreaker(rest.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		CoolDown:         50 * time.Millisecond,
+		HalfOpenRequests: 1,
+		OnStateChange: func(from, to rest.CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	client := rest.NewClient(rest.Config{
+		BaseURL:        server.URL,
+		Retry:          rest.NoRetry(),
+		CircuitBreaker: breaker,
+	})
+	do := func() error {
+		return client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+	}
+
+	for range 2 {
+		if err := do(); !rest.IsServerError(err) {
+			t.Fatalf("Client.Do() error = %v, want server error", err)
+		}
+	}
+	if breaker.State() != rest.CircuitOpen {
+		t.Fatalf("State() = %v, want %v", breaker.State(), rest.CircuitOpen)
+	}
+
+	if err := do(); !errors.Is(err, rest.ErrCircuitOpen) {
+		t.Errorf("Client.Do() error = %v, want %v", err, rest.ErrCircuitOpen)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+
+	// failed trial request opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	if err := do(); !rest.IsServerError(err) {
+		t.Fatalf("Client.Do() error = %v, want server error", err)
+	}
+	if breaker.State() != rest.CircuitOpen {
+		t.Fatalf("State() = %v, want %v", breaker.State(), rest.CircuitOpen)
+	}
+
+	// successful trial request closes the circuit
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if err := do(); err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+	if breaker.State() != rest.CircuitClosed {
+		t.Fatalf("State() = %v, want %v", breaker.State(), rest.CircuitClosed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transitions = %v, want %v", transitions, want)
+			break
+		}
+	}
+}
+
+func TestCircuitBreaker_ClientErrorsAreSuccesses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	breaker := rest.NewCircuitBreaker(rest.CircuitBreakerConfig{FailureThreshold: 1})
+	client := rest.NewClient(rest.Config{
+		BaseURL:        server.URL,
+		CircuitBreaker: breaker,
+	})
+
+	for range 3 {
+		if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil); !rest.IsNotFound(err) {
+			t.Fatalf("Client.Do() error = %v, want not found", err)
+		}
+	}
+	if breaker.State() != rest.CircuitClosed {
+		t.Errorf("State() = %v, want %v", breaker.State(), rest.CircuitClosed)
+	}
+}
+
+func TestCircuitBreaker_StaleOutcomes(t *testing.T) {
+	slowRelease, trialRelease := make(chan struct{}), make(chan struct{})
+	var slowStarted, trialStarted atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			slowStarted.Store(true)
+			<-slowRelease
+		case "/trial":
+			trialStarted.Store(true)
+			<-trialRelease
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	breaker := rest.NewCircuitBreaker(rest.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         50 * time.Millisecond,
+		HalfOpenRequests: 1,
+	})
+	client := rest.NewClient(rest.Config{
+		BaseURL:        server.URL,
+		Retry:          rest.NoRetry(),
+		CircuitBreaker: breaker,
+	})
+	do := func(path string) error {
+		return client.Do(context.Background(), http.MethodGet, path, nil, nil, nil)
+	}
+
+	// admitted while the circuit is closed
+	slowDone := make(chan error)
+	go func() { slowDone <- do("/slow") }()
+	waitFor(t, slowStarted.Load)
+
+	if err := do("/fail"); !rest.IsServerError(err) {
+		t.Fatalf("Client.Do() error = %v, want server error", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	trialDone := make(chan error)
+	go func() { trialDone <- do("/trial") }()
+	waitFor(t, trialStarted.Load)
+
+	// the outcome of the request sent before the circuit opened is ignored
+	close(slowRelease)
+	if err := <-slowDone; err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+	if breaker.State() != rest.CircuitHalfOpen {
+		t.Errorf("State() = %v, want %v", breaker.State(), rest.CircuitHalfOpen)
+	}
+	if err := do("/"); !errors.Is(err, rest.ErrCircuitOpen) {
+		t.Errorf("Client.Do() error = %v, want %v", err, rest.ErrCircuitOpen)
+	}
+
+	close(trialRelease)
+	if err := <-trialDone; err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+	if breaker.State() != rest.CircuitClosed {
+		t.Errorf("State() = %v, want %v", breaker.State(), rest.CircuitClosed)
+	}
+}
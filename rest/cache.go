@@ -0,0 +1,312 @@
+package rest
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultCacheCapacity = 1000
+
+// CacheEntry is a cached response.
+type CacheEntry struct {
+	Header    http.Header // Response headers
+	Body      []byte      // Raw response body, possibly compressed as described by the headers
+	ExpiresAt time.Time   // The entry is served without revalidation until this time
+}
+
+// CacheStore stores cached responses. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	// DeletePrefix removes all entries with keys starting with prefix.
+	DeletePrefix(prefix string)
+}
+
+// CacheConfig configures a Cache.
+type CacheConfig struct {
+	// Optional storage, defaults to an in-memory LRU store of 1000 entries.
+	Store CacheStore
+	// Optional freshness of responses without validators and max-age. Such
+	// responses are not cached if TTL is not set.
+	TTL time.Duration
+}
+
+// Cache caches successful GET responses of the paths allowed by the client.
+//
+// Responses with an ETag or Last-Modified header are revalidated with
+// If-None-Match and If-Modified-Since, unless Cache-Control max-age says they
+// are still fresh. Other responses are served from the cache for TTL.
+// Responses with Cache-Control no-store are never cached.
+//
+// Entries are keyed by path, query, host and a hash of the Authorization
+// header. A request with any other method invalidates cached responses of the
+// first path segment, so that `DELETE /webhooks/1` invalidates `GET /webhooks`.
+//
+// It is safe for concurrent use and can be shared between clients.
+type Cache struct {
+	store CacheStore
+	ttl   time.Duration
+}
+
+func NewCache(config CacheConfig) *Cache {
+	if config.Store == nil {
+		config.Store = NewLRUCacheStore(defaultCacheCapacity)
+	}
+
+	return &Cache{
+		store: config.Store,
+		ttl:   config.TTL,
+	}
+}
+
+// roundTrip sends the request through the cache. path is the request path
+// relative to the base URL, cacheable tells whether a GET response of the path
+// may be cached and limit is the maximum size of cached bodies.
+func (c *Cache) roundTrip(
+	req *http.Request, path string, cacheable bool, limit int64, next RoundTripFunc,
+) (*http.Response, error) {
+	if c == nil {
+		return next(req)
+	}
+
+	if req.Method != http.MethodGet {
+		defer c.store.DeletePrefix(http.MethodGet + " " + collection(path))
+		return next(req)
+	}
+
+	if !cacheable || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		// conditional requests of the caller are passed through
+		return next(req)
+	}
+
+	key := cacheKey(req, path)
+	entry, cached := c.store.Get(key)
+	if cached {
+		if time.Now().Before(entry.ExpiresAt) {
+			return entry.response(req), nil
+		}
+
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := next(req)
+	if err != nil {
+		return resp, err
+	}
+
+	switch {
+	case cached && resp.StatusCode == http.StatusNotModified:
+		resp.Body.Close()
+		for _, k := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if v := resp.Header.Values(k); len(v) > 0 {
+				entry.Header[k] = v
+			}
+		}
+		entry.ExpiresAt = c.expiresAt(entry.Header)
+		c.store.Set(key, entry)
+
+		return entry.response(req), nil
+	case resp.StatusCode == http.StatusOK:
+		return c.store200(key, resp, limit), nil
+	default:
+		return resp, nil
+	}
+}
+
+// store200 caches the successful response if it is cacheable and not larger than limit.
+func (c *Cache) store200(key string, resp *http.Response, limit int64) *http.Response {
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp
+	}
+
+	header := resp.Header.Clone()
+	if header.Get("ETag") == "" && header.Get("Last-Modified") == "" && c.ttl <= 0 && maxAge(header) <= 0 {
+		return resp
+	}
+
+	if limit >= 0 {
+		limit++
+	}
+	body, err := io.ReadAll(limitReader(resp.Body, limit))
+	if err != nil || (limit >= 0 && int64(len(body)) >= limit) {
+		// return the response as is and let the caller handle the error or the size
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.store.Set(key, CacheEntry{
+		Header:    header,
+		Body:      body,
+		ExpiresAt: c.expiresAt(header),
+	})
+
+	return resp
+}
+
+// expiresAt returns the freshness deadline of a response with the given headers.
+func (c *Cache) expiresAt(header http.Header) time.Time {
+	now := time.Now()
+
+	if strings.Contains(header.Get("Cache-Control"), "no-cache") {
+		return now
+	}
+	if age := maxAge(header); age > 0 {
+		return now.Add(age)
+	}
+	if header.Get("ETag") != "" || header.Get("Last-Modified") != "" {
+		// revalidate every time
+		return now
+	}
+
+	return now.Add(c.ttl)
+}
+
+func (e CacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// maxAge returns the Cache-Control max-age of the response.
+func maxAge(header http.Header) time.Duration {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age=")
+		if !ok {
+			continue
+		}
+
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
+
+// cacheKey identifies a GET request by path, query, host and credentials.
+func cacheKey(req *http.Request, path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+
+	return http.MethodGet + " " + path + "?" + req.URL.RawQuery + " " + req.URL.Host + " " + hex.EncodeToString(auth[:8])
+}
+
+// collection returns the first segment of the path, such as `/webhooks` for `/webhooks/123`.
+func collection(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	segment, _, _ = strings.Cut(segment, "?")
+
+	return "/" + segment
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// LRUCacheStore is an in-memory CacheStore that evicts the least recently
+// used entries when full.
+type LRUCacheStore struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+}
+
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+func item(element *list.Element) *lruItem {
+	item, _ := element.Value.(*lruItem)
+	return item
+}
+
+// NewLRUCacheStore creates a store of up to capacity entries, defaults to 1000.
+func NewLRUCacheStore(capacity int) *LRUCacheStore {
+	if capacity <= 0 {
+		capacity = defaultCacheCapacity
+	}
+
+	return &LRUCacheStore{
+		capacity: capacity,
+
+		mu:      sync.Mutex{},
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (s *LRUCacheStore) Get(key string) (CacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	s.order.MoveToFront(element)
+
+	entry := item(element).entry
+	entry.Header = entry.Header.Clone()
+
+	return entry, true
+}
+
+func (s *LRUCacheStore) Set(key string, entry CacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		item(element).entry = entry
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&lruItem{key: key, entry: entry})
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, item(oldest).key)
+	}
+}
+
+func (s *LRUCacheStore) DeletePrefix(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, element := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			s.order.Remove(element)
+			delete(s.entries, key)
+		}
+	}
+}
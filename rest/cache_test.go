@@ -0,0 +1,180 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestClient_Do_Cache(t *testing.T) {
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+	tests := []struct {
+		name         string
+		header       map[string]string
+		ttl          time.Duration
+		cachePaths   []string
+		between      func(client *rest.Client) // called between the two GETs
+		auth         [2]string
+		wantRequests int32 // GET requests reaching the server
+		wantNotMod   int32
+	}{
+		{
+			name:         "ETag",
+			header:       map[string]string{"ETag": `"v1"`},
+			wantRequests: 2,
+			wantNotMod:   1,
+		},
+		{
+			name:         "Last-Modified",
+			header:       map[string]string{"Last-Modified": lastModified},
+			wantRequests: 2,
+			wantNotMod:   1,
+		},
+		{
+			name:         "Max-age",
+			header:       map[string]string{"ETag": `"v1"`, "Cache-Control": "max-age=60"},
+			wantRequests: 1,
+		},
+		{
+			name:         "TTL",
+			ttl:          time.Hour,
+			wantRequests: 1,
+		},
+		{
+			name:         "Without TTL",
+			wantRequests: 2,
+		},
+		{
+			name:         "Allowed path",
+			ttl:          time.Hour,
+			cachePaths:   []string{"/device", "/webhooks"},
+			wantRequests: 1,
+		},
+		{
+			name:         "Other path",
+			ttl:          time.Hour,
+			cachePaths:   []string{"/device"},
+			wantRequests: 2,
+		},
+		{
+			name:         "No-store",
+			header:       map[string]string{"Cache-Control": "no-store"},
+			ttl:          time.Hour,
+			wantRequests: 2,
+		},
+		{
+			name: "Invalidated",
+			ttl:  time.Hour,
+			between: func(client *rest.Client) {
+				_ = client.Do(context.Background(), http.MethodDelete, "/webhooks/1", nil, nil, nil)
+			},
+			wantRequests: 2,
+		},
+		{
+			name: "Not invalidated by other resources",
+			ttl:  time.Hour,
+			between: func(client *rest.Client) {
+				_ = client.Do(context.Background(), http.MethodPost, "/message", nil, nil, nil)
+			},
+			wantRequests: 1,
+		},
+		{
+			name:         "Other credentials",
+			ttl:          time.Hour,
+			auth:         [2]string{"Basic a", "Basic b"},
+			wantRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests, notModified atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				requests.Add(1)
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				if r.Header.Get("If-None-Match") == tt.header["ETag"] && r.Header.Get("If-None-Match") != "" ||
+					r.Header.Get("If-Modified-Since") == lastModified && tt.header["Last-Modified"] != "" {
+					notModified.Add(1)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = w.Write([]byte(`[{"id":"1"}]`))
+			}))
+			defer server.Close()
+
+			client := rest.NewClient(rest.Config{
+				BaseURL:    server.URL,
+				Retry:      rest.NoRetry(),
+				Cache:      rest.NewCache(rest.CacheConfig{TTL: tt.ttl}),
+				CachePaths: tt.cachePaths,
+			})
+
+			for i, auth := range tt.auth {
+				if i == 1 && tt.between != nil {
+					tt.between(client)
+				}
+
+				var headers map[string]string
+				if auth != "" {
+					headers = map[string]string{"Authorization": auth}
+				}
+
+				got, err := rest.Do[[]map[string]string](context.Background(), client, rest.Request{
+					Method:  http.MethodGet,
+					Path:    "/webhooks",
+					Headers: headers,
+				})
+				if err != nil {
+					t.Fatalf("Do() error = %v", err)
+				}
+				if len(got) != 1 || got[0]["id"] != "1" {
+					t.Errorf("Do() = %v, want cached body", got)
+				}
+			}
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if got := notModified.Load(); got != tt.wantNotMod {
+				t.Errorf("304 responses = %d, want %d", got, tt.wantNotMod)
+			}
+		})
+	}
+}
+
+func TestLRUCacheStore(t *testing.T) {
+	store := rest.NewLRUCacheStore(2)
+	entry := rest.CacheEntry{Header: http.Header{}, Body: []byte("{}")}
+
+	store.Set("GET /a", entry)
+	store.Set("GET /b", entry)
+	store.Get("GET /a")
+	store.Set("GET /c", entry)
+
+	if _, ok := store.Get("GET /b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok := store.Get("GET /a"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+
+	store.DeletePrefix("GET /")
+	for _, key := range []string{"GET /a", "GET /c"} {
+		if _, ok := store.Get(key); ok {
+			t.Errorf("entry %q was not deleted", key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"context"
+	"time"
+)
+
+// CallOption customizes a single call.
+type CallOption func(*CallOptions)
+
+// CallOptions are the settings of a single call that override the client config.
+type CallOptions struct {
+	Timeout time.Duration     // Timeout of the whole call including retries
+	Headers map[string]string // Extra request headers
+	Retry   *RetryPolicy      // Retry policy of the call
+	BaseURL string            // Base URL of the call
+}
+
+// NewCallOptions applies options to empty CallOptions.
+func NewCallOptions(options ...CallOption) CallOptions {
+	o := CallOptions{
+		Timeout: 0,
+		Headers: nil,
+		Retry:   nil,
+		BaseURL: "",
+	}
+	for _, option := range options {
+		option(&o)
+	}
+
+	return o
+}
+
+// CallTimeout limits the duration of the call including retries, rate limit
+// waits and failover between endpoints.
+func CallTimeout(timeout time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// CallHeader sets an extra request header.
+func CallHeader(name, value string) CallOption {
+	return func(o *CallOptions) {
+		if o.Headers == nil {
+			o.Headers = map[string]string{}
+		}
+		o.Headers[name] = value
+	}
+}
+
+// CallRetryPolicy overrides the retry policy of the client.
+func CallRetryPolicy(policy *RetryPolicy) CallOption {
+	return func(o *CallOptions) {
+		o.Retry = policy
+	}
+}
+
+// CallBaseURL sends the call to another base URL.
+func CallBaseURL(baseURL string) CallOption {
+	return func(o *CallOptions) {
+		o.BaseURL = baseURL
+	}
+}
+
+// WithTimeout returns ctx limited by the call timeout. Clients that send a
+// call as several requests apply it once to share the timeout between them.
+func (o CallOptions) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.Timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, o.Timeout)
+}
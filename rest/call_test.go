@@ -0,0 +1,128 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestClient_Do_CallOptions(t *testing.T) {
+	var primaryHits, otherHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		case "/fail":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"value":"` + r.Header.Get("X-Tenant") + `"}`))
+	}))
+	defer primary.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		otherHits.Add(1)
+		_, _ = w.Write([]byte(`{"value":"other"}`))
+	}))
+	defer other.Close()
+
+	tests := []struct {
+		name            string
+		path            string
+		headers         map[string]string
+		options         []rest.CallOption
+		want            string
+		wantErr         func(error) bool
+		wantPrimaryHits int32
+		wantOtherHits   int32
+	}{
+		{
+			name:            "No options",
+			path:            "/",
+			headers:         map[string]string{"X-Tenant": "static"},
+			want:            "static",
+			wantPrimaryHits: 1,
+		},
+		{
+			name:            "Header",
+			path:            "/",
+			headers:         map[string]string{"X-Tenant": "static"},
+			options:         []rest.CallOption{rest.CallHeader("X-Tenant", "call")},
+			want:            "call",
+			wantPrimaryHits: 1,
+		},
+		{
+			name:            "Timeout",
+			path:            "/slow",
+			options:         []rest.CallOption{rest.CallTimeout(10 * time.Millisecond)},
+			wantErr:         func(err error) bool { return errors.Is(err, context.DeadlineExceeded) },
+			wantPrimaryHits: 1,
+		},
+		{
+			name:            "Client retry policy",
+			path:            "/fail",
+			wantErr:         rest.IsServerError,
+			wantPrimaryHits: 3,
+		},
+		{
+			name:            "Retry policy",
+			path:            "/fail",
+			options:         []rest.CallOption{rest.CallRetryPolicy(rest.NoRetry())},
+			wantErr:         rest.IsServerError,
+			wantPrimaryHits: 1,
+		},
+		{
+			name:          "Base URL",
+			path:          "/",
+			options:       []rest.CallOption{rest.CallBaseURL(other.URL)},
+			want:          "other",
+			wantOtherHits: 1,
+		},
+	}
+
+	client := rest.NewClient(rest.Config{
+		BaseURL: primary.URL,
+		Retry: &rest.RetryPolicy{
+			MaxAttempts:       3,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        time.Millisecond,
+			RetryableStatuses: []int{http.StatusServiceUnavailable},
+		},
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primaryHits.Store(0)
+			otherHits.Store(0)
+
+			resp, err := rest.Do[struct{ Value string }](context.Background(), client, rest.Request{
+				Method:  http.MethodGet,
+				Path:    tt.path,
+				Headers: tt.headers,
+				Options: tt.options,
+			})
+			if tt.wantErr != nil {
+				if err == nil || !tt.wantErr(err) {
+					t.Errorf("Do() error = %v, want matching error", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if resp.Value != tt.want {
+				t.Errorf("Do() value = %q, want %q", resp.Value, tt.want)
+			}
+			if got := primaryHits.Load(); got != tt.wantPrimaryHits {
+				t.Errorf("primary hits = %d, want %d", got, tt.wantPrimaryHits)
+			}
+			if got := otherHits.Load(); got != tt.wantOtherHits {
+				t.Errorf("other hits = %d, want %d", got, tt.wantOtherHits)
+			}
+		})
+	}
+}
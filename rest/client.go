@@ -3,72 +3,413 @@ package rest
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	Client  *http.Client // Optional HTTP Client, defaults to `http.DefaultClient`
+	Client  *http.Client // Optional HTTP Client, defaults to a client created with `Transport`
 	BaseURL string       // Optional base URL
+	Retry   *RetryPolicy // Optional retry policy, defaults to `DefaultRetryPolicy()`
+
+	Transport TransportConfig // Optional tuning of the default HTTP client, ignored if `Client` is set
+
+	Authenticator Authenticator // Optional authenticator, invoked for every attempt
+
+	// Optional TLS hardening, applied to a copy of the transport of `Client`,
+	// which must be an `*http.Transport` or nil.
+	TLS TLSConfig
+
+	CircuitBreaker *CircuitBreaker // Optional circuit breaker, disabled by default
+	Cache          *Cache          // Optional cache of GET responses, disabled by default
+	CachePaths     []string        // Optional paths of cacheable GET requests without query, all paths if empty
+
+	Middlewares []Middleware // Optional middlewares, applied to every attempt in order
+
+	Compression Compression // Optional compression of request bodies
+
+	// Share a single in-flight request between concurrent identical GET
+	// requests made with DoRequest. The shared request uses the context values,
+	// such as the request ID, of the first caller. Streams are not coalesced.
+	CoalesceRequests bool
+
+	MaxResponseSize  int64 // Optional limit of response body size, defaults to 10 MiB, negative means no limit
+	MaxErrorBodySize int64 // Optional limit of error body size, longer bodies are truncated, defaults to 64 KiB
+
+	Logger    *slog.Logger // Optional logger, logging is disabled by default
+	LogBodies bool         // Log request and response bodies at debug level
+	Redact    Redaction    // Masking of sensitive data in logs and API errors
 }
 
 type Client struct {
 	config Config
+
+	roundTrip RoundTripFunc
+	flights   *flightGroup // nil unless requests are coalesced
+	initErr   error        // returned by every call if the config is invalid
 }
 
+// Do sends a request with an optional JSON payload and decodes the JSON response into response.
 func (c *Client) Do(ctx context.Context, method, path string, headers map[string]string, payload, response any) error {
-	var reqBody io.Reader
-	if payload != nil {
-		jsonBytes, err := json.Marshal(payload)
+	return c.DoRequest(
+		ctx,
+		Request{
+			Method:  method,
+			Path:    path,
+			Query:   nil,
+			Headers: headers,
+			Payload: payload,
+			Options: nil,
+		},
+		response,
+	)
+}
+
+// DoRequest sends the request and decodes the JSON response into response.
+func (c *Client) DoRequest(ctx context.Context, req Request, response any) error {
+	req, opts := c.resolve(req)
+	ctx, cancel := opts.WithTimeout(ctx)
+	defer cancel()
+
+	if c.flights != nil && req.Method == http.MethodGet {
+		body, err := c.flights.do(ctx, req.key(opts.BaseURL), func(ctx context.Context) ([]byte, error) {
+			var body []byte
+			err := c.execute(ctx, req, opts, func(resp *http.Response) error {
+				var err error
+				body, err = c.read(ctx, resp)
+				return err
+			})
+			return body, err
+		})
+		if err != nil || body == nil {
+			return err
+		}
+
+		return unmarshal(body, response)
+	}
+
+	return c.execute(ctx, req, opts, func(resp *http.Response) error {
+		return c.decode(ctx, resp, response)
+	})
+}
+
+// resolve merges the call option headers into the request and fills in the
+// base URL and retry policy of the client if the call options don't override them.
+func (c *Client) resolve(req Request) (Request, CallOptions) {
+	opts := NewCallOptions(req.Options...)
+	if opts.BaseURL == "" {
+		opts.BaseURL = c.config.BaseURL
+	}
+	if opts.Retry == nil {
+		opts.Retry = c.config.Retry
+	}
+
+	if len(opts.Headers) > 0 {
+		headers := make(map[string]string, len(req.Headers)+len(opts.Headers))
+		for k, v := range req.Headers {
+			headers[k] = v
+		}
+		for k, v := range opts.Headers {
+			headers[k] = v
+		}
+		req.Headers = headers
+	}
+
+	return req, opts
+}
+
+// execute sends the resolved request with retries and passes successful responses to handle.
+func (c *Client) execute(ctx context.Context, req Request, opts CallOptions, handle func(*http.Response) error) error {
+	if c.initErr != nil {
+		return fmt.Errorf("failed to initialize client: %w", c.initErr)
+	}
+
+	// all attempts share the request ID
+	ctx = ensureRequestID(ctx)
+
+	var reqBody *requestBody
+	if req.Payload != nil {
+		body, err := encodeBody(req.Payload)
 		if err != nil {
-			return fmt.Errorf("failed to marshal payload: %w", err)
+			return err
 		}
-		reqBody = strings.NewReader(string(jsonBytes))
+		defer body.release()
+
+		if err := c.config.Compression.compress(body); err != nil {
+			return err
+		}
+		reqBody = body
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, reqBody)
+	policy := opts.Retry
+	attempts := policy.maxAttempts(req.Method)
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		err := c.attempt(ctx, req, opts.BaseURL, reqBody, handle)
+		if err == nil {
+			return nil
+		}
+
+		var delivered *deliveredError
+		if errors.As(err, &delivered) {
+			return delivered.err
+		}
+
+		if !refreshed && c.refresh(ctx, err) {
+			// repeat the attempt once with renewed credentials
+			refreshed = true
+			attempt--
+			continue
+		}
+
+		if attempt >= attempts || !policy.shouldRetry(ctx, err) {
+			c.log(ctx, slog.LevelError, "request failed",
+				slog.String("method", req.Method),
+				slog.String("path", req.Path),
+				slog.Int("attempt", attempt),
+				slog.Any("error", err),
+			)
+			return err
+		}
+
+		delay := policy.Delay(attempt, err)
+		c.log(ctx, slog.LevelWarn, "retrying request",
+			slog.String("method", req.Method),
+			slog.String("path", req.Path),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+
+		if sleepErr := Sleep(ctx, delay); sleepErr != nil {
+			return fmt.Errorf("failed to wait for retry: %w", sleepErr)
+		}
+	}
+}
+
+// attempt performs a single request attempt guarded by the circuit breaker.
+func (c *Client) attempt(
+	ctx context.Context, req Request, baseURL string, reqBody *requestBody, handle func(*http.Response) error,
+) error {
+	permit, err := c.config.CircuitBreaker.allow()
+	if err != nil {
+		return err
+	}
+
+	err = c.do(ctx, req, baseURL, reqBody, handle)
+	c.config.CircuitBreaker.record(ctx, permit, err)
+
+	return err
+}
+
+// refresh renews the credentials after a 401 response and reports whether
+// the request should be repeated.
+func (c *Client) refresh(ctx context.Context, err error) bool {
+	refresher, ok := c.config.Authenticator.(Refresher)
+	if !ok {
+		return false
+	}
+
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	if refreshErr := refresher.Refresh(ctx, apiErr.request); refreshErr != nil {
+		c.log(ctx, slog.LevelWarn, "failed to refresh credentials", slog.Any("error", refreshErr))
+		return false
+	}
+
+	return true
+}
+
+// do performs a single request.
+func (c *Client) do(
+	ctx context.Context, r Request, baseURL string, reqBody *requestBody, handle func(*http.Response) error,
+) error {
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.url(baseURL), nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	if reqBody != nil {
+		// the transport closes the readers, releasing the pooled buffers
+		req.Body = reqBody.reader()
+		req.GetBody = func() (io.ReadCloser, error) { return reqBody.reader(), nil }
+		req.ContentLength = int64(len(reqBody.data))
+
 		req.Header.Set("Content-Type", "application/json")
+		if reqBody.encoding != "" {
+			req.Header.Set("Content-Encoding", reqBody.encoding)
+		}
 	}
-	for k, v := range headers {
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	req.Header.Set(RequestIDHeader, RequestIDFromContext(ctx))
+	for k, v := range r.Headers {
 		req.Header.Set(k, v)
 	}
+	if c.config.Authenticator != nil {
+		if err := c.config.Authenticator.Authenticate(req); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
+	c.logRequest(ctx, req, reqBody)
 
-	resp, err := c.config.Client.Do(req)
+	start := time.Now()
+	resp, err := c.config.Cache.roundTrip(req, r.Path, c.cacheable(r.Path), c.config.MaxResponseSize, c.roundTrip)
 	if err != nil {
 		return fmt.Errorf("failed to make request: %w", err)
 	}
+	decompressResponse(resp)
+	captureResponse(ctx, req, resp)
 	defer func() {
-		_, _ = io.Copy(io.Discard, resp.Body)
+		// drain a bounded amount of the remaining body to allow connection reuse
+		_, _ = io.CopyN(io.Discard, resp.Body, maxDrainSize)
 		resp.Body.Close()
 	}()
 
+	c.logResponse(ctx, req, resp, time.Since(start))
+
 	if resp.StatusCode >= http.StatusBadRequest {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("%w: unexpected status code %d with body %s", ErrAPIError, resp.StatusCode, string(body))
+		body, _ := io.ReadAll(limitReader(resp.Body, c.config.MaxErrorBodySize))
+		apiErr := newAPIError(resp.StatusCode, resp.Header, body)
+		apiErr.Body = c.config.Redact.errorBody(body)
+		apiErr.RequestID = responseRequestID(req, resp)
+		apiErr.request = req
+		c.logBody(ctx, "error body", body)
+		return apiErr
 	}
 
 	if resp.StatusCode == http.StatusNoContent {
 		return nil
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	return handle(resp)
+}
+
+// cacheable reports whether GET responses of the path may be cached.
+func (c *Client) cacheable(path string) bool {
+	if len(c.config.CachePaths) == 0 {
+		return true
+	}
+
+	path, _, _ = strings.Cut(path, "?")
+
+	return slices.Contains(c.config.CachePaths, path)
+}
+
+// decode reads the whole response body and decodes it as JSON into response.
+func (c *Client) decode(ctx context.Context, resp *http.Response, response any) error {
+	body, err := c.read(ctx, resp)
+	if err != nil {
+		return err
+	}
+
+	return unmarshal(body, response)
+}
+
+// read reads the whole response body within the size limit.
+func (c *Client) read(ctx context.Context, resp *http.Response) ([]byte, error) {
+	limit := c.config.MaxResponseSize
+	if limit >= 0 {
+		// read one byte more to detect oversized responses
+		limit++
+	}
+
+	body, err := io.ReadAll(limitReader(resp.Body, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if c.config.MaxResponseSize >= 0 && int64(len(body)) > c.config.MaxResponseSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, c.config.MaxResponseSize)
+	}
+
+	c.logBody(ctx, "response body", body)
+
+	return body, nil
+}
+
+func unmarshal(body []byte, response any) error {
+	if err := json.Unmarshal(body, &response); err != nil {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	return nil
 }
 
+func (c *Client) logRequest(ctx context.Context, req *http.Request, body *requestBody) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+		slog.Any("headers", redactHeaders(req.Header)),
+	}
+	if c.config.LogBodies && body != nil {
+		attrs = append(attrs, slog.String("body", string(c.config.Redact.requestBody(body.raw))))
+	}
+
+	c.log(ctx, slog.LevelDebug, "sending request", attrs...)
+}
+
+func (c *Client) logResponse(ctx context.Context, req *http.Request, resp *http.Response, duration time.Duration) {
+	c.log(ctx, slog.LevelDebug, "received response",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", duration),
+		slog.Any("headers", redactHeaders(resp.Header)),
+	)
+}
+
+func (c *Client) logBody(ctx context.Context, msg string, body []byte) {
+	if !c.config.LogBodies || len(body) == 0 {
+		return
+	}
+
+	c.log(ctx, slog.LevelDebug, msg, slog.String("body", string(c.config.Redact.body(body))))
+}
+
 func NewClient(config Config) *Client {
 	if config.Client == nil {
-		config.Client = http.DefaultClient
+		config.Client = NewHTTPClient(config.Transport)
+	}
+	if config.Retry == nil {
+		config.Retry = DefaultRetryPolicy()
+	}
+	if config.MaxResponseSize == 0 {
+		config.MaxResponseSize = DefaultMaxResponseSize
+	}
+	if config.MaxErrorBodySize == 0 {
+		config.MaxErrorBodySize = DefaultMaxErrorBodySize
 	}
 
-	return &Client{config: config}
+	var initErr error
+	if !config.TLS.isZero() {
+		if client, err := withTLS(config.Client, config.TLS); err != nil {
+			initErr = err
+		} else {
+			config.Client = client
+		}
+	}
+
+	var flights *flightGroup
+	if config.CoalesceRequests {
+		flights = newFlightGroup()
+	}
+
+	return &Client{
+		config:    config,
+		roundTrip: chain(config.Client.Do, config.Middlewares),
+		flights:   flights,
+		initErr:   initErr,
+	}
 }
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"context"
+	"sync"
+)
+
+// flight is an in-flight request shared by several callers.
+type flight struct {
+	done chan struct{}
+	body []byte
+	info ResponseInfo // last response of the shared call, zero if there was none
+	err  error
+
+	waiters int // callers still waiting for the result
+	cancel  context.CancelFunc
+}
+
+// flightGroup coalesces concurrent calls with the same key into one.
+//
+// The shared call runs with the values of the first caller's context but
+// isn't canceled with it: every caller stops waiting when its own context is
+// done, and the shared call is canceled once no callers are left. The
+// response details are stored in the ResponseInfo of each caller that gets
+// the result, never in that of the first caller after it has stopped waiting.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		mu:      sync.Mutex{},
+		flights: map[string]*flight{},
+	}
+}
+
+// do calls fn once for concurrent callers with the same key and returns its result to all of them.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if !ok {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{
+			done: make(chan struct{}),
+			body: nil,
+			info: ResponseInfo{StatusCode: 0, Header: nil, RequestID: ""},
+			err:  nil,
+
+			waiters: 0,
+			cancel:  cancel,
+		}
+		g.flights[key] = f
+
+		// the response details are captured by the flight instead of the first caller
+		flightCtx = CaptureResponse(flightCtx, &f.info)
+
+		go func() {
+			f.body, f.err = fn(flightCtx)
+
+			g.mu.Lock()
+			g.forget(key, f)
+			g.mu.Unlock()
+
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if info, ok := ctx.Value(responseInfoKey{}).(*ResponseInfo); ok && info != nil && f.info.StatusCode != 0 {
+			*info = f.info
+		}
+		return f.body, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// later callers start a new flight instead of joining the canceled one
+			g.forget(key, f)
+			f.cancel()
+		}
+		g.mu.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes the flight if it is still registered. Must be called with mu held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
@@ -0,0 +1,228 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+// newBlockingServer returns a server that responds once release is closed.
+func newBlockingServer(t *testing.T, release chan struct{}, requests *atomic.Int32, canceled chan<- struct{}) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-release:
+			_, _ = w.Write([]byte(`{"id":"123"}`))
+		case <-r.Context().Done():
+			if canceled != nil {
+				canceled <- struct{}{}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_Do_Coalesce(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := newBlockingServer(t, release, &requests, nil)
+
+	client := rest.NewClient(rest.Config{BaseURL: server.URL, CoalesceRequests: true})
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan map[string]string, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := rest.Do[map[string]string](context.Background(), client, rest.Request{Method: http.MethodGet, Path: "/message/123"})
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+			results <- resp
+		}()
+	}
+
+	waitFor(t, func() bool { return requests.Load() > 0 })
+	time.Sleep(20 * time.Millisecond) // let the other callers join
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	for resp := range results {
+		if resp["id"] != "123" {
+			t.Errorf("Do() = %v, want shared response", resp)
+		}
+	}
+}
+
+func TestClient_Do_CoalesceCancel(t *testing.T) {
+	release := make(chan struct{})
+	canceled := make(chan struct{}, 1)
+	var requests atomic.Int32
+	server := newBlockingServer(t, release, &requests, canceled)
+
+	client := rest.NewClient(rest.Config{BaseURL: server.URL, Retry: rest.NoRetry(), CoalesceRequests: true})
+	req := rest.Request{Method: http.MethodGet, Path: "/message/123"}
+
+	// the first caller gives up, the second one still gets the response
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := rest.Do[map[string]string](firstCtx, client, req)
+		firstErr <- err
+	}()
+	waitFor(t, func() bool { return requests.Load() > 0 })
+
+	secondResp := make(chan map[string]string, 1)
+	go func() {
+		resp, _ := rest.Do[map[string]string](context.Background(), client, req)
+		secondResp <- resp
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("first Do() error = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if resp := <-secondResp; resp["id"] != "123" {
+		t.Errorf("second Do() = %v, want response", resp)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestClient_Do_CoalesceCancelAll(t *testing.T) {
+	canceled := make(chan struct{}, 1)
+	var requests atomic.Int32
+	server := newBlockingServer(t, make(chan struct{}), &requests, canceled)
+
+	client := rest.NewClient(rest.Config{BaseURL: server.URL, Retry: rest.NoRetry(), CoalesceRequests: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := rest.Do[map[string]string](ctx, client, rest.Request{Method: http.MethodGet, Path: "/message/123"})
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Do() error = %v, want %v", err, context.DeadlineExceeded)
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("shared request was not canceled after all callers left")
+	}
+}
+
+func TestClient_Do_CoalesceDistinct(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{BaseURL: server.URL, CoalesceRequests: true})
+
+	reqs := []rest.Request{
+		{Method: http.MethodGet, Path: "/message/1"},
+		{Method: http.MethodGet, Path: "/message/2"},
+		{Method: http.MethodGet, Path: "/message/1", Headers: map[string]string{"X-Tenant": "a"}},
+		{Method: http.MethodPost, Path: "/message"},
+		{Method: http.MethodPost, Path: "/message"},
+	}
+
+	var wg sync.WaitGroup
+	for _, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = rest.Do[map[string]string](context.Background(), client, req)
+		}()
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != int32(len(reqs)) {
+		t.Errorf("requests = %d, want %d", got, len(reqs))
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClient_Do_CoalesceResponseInfo(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := newBlockingServer(t, release, &requests, nil)
+
+	client := rest.NewClient(rest.Config{BaseURL: server.URL, Retry: rest.NoRetry(), CoalesceRequests: true})
+	req := rest.Request{Method: http.MethodGet, Path: "/message/123"}
+
+	var firstInfo, secondInfo rest.ResponseInfo
+	firstCtx, cancelFirst := context.WithCancel(rest.CaptureResponse(context.Background(), &firstInfo))
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := rest.Do[map[string]string](firstCtx, client, req)
+		firstErr <- err
+	}()
+	waitFor(t, func() bool { return requests.Load() > 0 })
+
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := rest.Do[map[string]string](rest.CaptureResponse(context.Background(), &secondInfo), client, req)
+		secondErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelFirst()
+	<-firstErr
+
+	close(release)
+	if err := <-secondErr; err != nil {
+		t.Fatalf("second Do() error = %v", err)
+	}
+
+	// the caller that gave up is not written to after it returned
+	if firstInfo.StatusCode != 0 {
+		t.Errorf("first ResponseInfo.StatusCode = %d, want 0", firstInfo.StatusCode)
+	}
+	if secondInfo.StatusCode != http.StatusOK || secondInfo.RequestID == "" {
+		t.Errorf("second ResponseInfo = %+v, want status 200 and request ID", secondInfo)
+	}
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const acceptEncoding = "gzip, deflate"
+
+// Compression configures compression of request bodies.
+//
+// Responses compressed with gzip or deflate are always decoded transparently.
+type Compression struct {
+	RequestThreshold int // Gzip request bodies of at least this many bytes, 0 disables request compression
+}
+
+//nolint:gochecknoglobals // shared pool
+var gzipWriterPool = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// compress gzips the payload if it reaches the threshold.
+func (c Compression) compress(body *requestBody) error {
+	if c.RequestThreshold <= 0 || len(body.raw) < c.RequestThreshold {
+		return nil
+	}
+
+	w, _ := gzipWriterPool.Get().(*gzip.Writer)
+	defer gzipWriterPool.Put(w)
+
+	w.Reset(&body.zbuf)
+	if _, err := w.Write(body.raw); err != nil {
+		return fmt.Errorf("failed to compress payload: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to compress payload: %w", err)
+	}
+
+	body.data = body.zbuf.Bytes()
+	body.encoding = "gzip"
+
+	return nil
+}
+
+// decompressResponse replaces the body of a gzip or deflate encoded response
+// with a decoding reader.
+func decompressResponse(resp *http.Response) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "deflate" {
+		return
+	}
+
+	resp.Body = &decompressingReader{
+		body:     resp.Body,
+		encoding: encoding,
+		r:        nil,
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// decompressingReader lazily creates the decompressor on the first read,
+// so empty bodies don't fail.
+type decompressingReader struct {
+	body     io.ReadCloser
+	encoding string
+	r        io.Reader
+}
+
+func (d *decompressingReader) Read(p []byte) (int, error) {
+	if d.r == nil {
+		r, err := d.newReader()
+		if err != nil {
+			return 0, err
+		}
+		d.r = r
+	}
+
+	return d.r.Read(p) //nolint:wrapcheck // io.Reader errors must not be wrapped
+}
+
+func (d *decompressingReader) newReader() (io.Reader, error) {
+	if d.encoding == "gzip" {
+		r, err := gzip.NewReader(d.body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip response: %w", err)
+		}
+		return r, nil
+	}
+
+	// "deflate" should be zlib-wrapped, but some servers send raw deflate data
+	br := bufio.NewReader(d.body)
+	header, err := br.Peek(2) //nolint:mnd // zlib header size
+	if err != nil && len(header) == 0 {
+		return nil, err //nolint:wrapcheck // io.Reader errors must not be wrapped
+	}
+
+	if isZlibHeader(header) {
+		r, err := zlib.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress deflate response: %w", err)
+		}
+		return r, nil
+	}
+
+	return flate.NewReader(br), nil
+}
+
+func (d *decompressingReader) Close() error {
+	return d.body.Close() //nolint:wrapcheck // io.Closer errors must not be wrapped
+}
+
+// isZlibHeader reports whether b starts with a valid zlib header (RFC 1950).
+func isZlibHeader(b []byte) bool {
+	//nolint:mnd // RFC 1950 constants
+	return len(b) == 2 && b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
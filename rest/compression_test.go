@@ -0,0 +1,121 @@
+package rest_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(buf)
+	case "deflate":
+		w = zlib.NewWriter(buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(buf, flate.DefaultCompression)
+	}
+	_, _ = w.Write(data)
+	_ = w.Close()
+
+	return buf.Bytes()
+}
+
+func TestClient_Do_Compression(t *testing.T) {
+	message := strings.Repeat("Привет, мир! ", 100)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip, deflate" {
+			t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+		}
+
+		var reqBody io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			reqBody = gz
+		}
+		payload, _ := io.ReadAll(reqBody)
+
+		encoding := r.URL.Query().Get("encoding")
+		w.Header().Set("X-Request-Encoding", r.Header.Get("Content-Encoding"))
+		if encoding == "" {
+			_, _ = w.Write(payload)
+			return
+		}
+
+		if encoding == "raw-deflate" {
+			w.Header().Set("Content-Encoding", "deflate")
+		} else {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		_, _ = w.Write(compress(t, encoding, payload))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		threshold        int
+		responseEncoding string
+		wantReqEncoding  string
+	}{
+		{name: "Plain", threshold: 0, responseEncoding: "", wantReqEncoding: ""},
+		{name: "Below threshold", threshold: 1 << 20, responseEncoding: "", wantReqEncoding: ""},
+		{name: "Gzip request", threshold: 100, responseEncoding: "", wantReqEncoding: "gzip"},
+		{name: "Gzip response", threshold: 100, responseEncoding: "gzip", wantReqEncoding: "gzip"},
+		{name: "Deflate response", threshold: 0, responseEncoding: "deflate", wantReqEncoding: ""},
+		{name: "Raw deflate response", threshold: 0, responseEncoding: "raw-deflate", wantReqEncoding: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reqEncoding string
+			client := rest.NewClient(rest.Config{
+				BaseURL:     server.URL,
+				Compression: rest.Compression{RequestThreshold: tt.threshold},
+				Middlewares: []rest.Middleware{
+					func(next rest.RoundTripFunc) rest.RoundTripFunc {
+						return func(req *http.Request) (*http.Response, error) {
+							resp, err := next(req)
+							if resp != nil {
+								reqEncoding = resp.Header.Get("X-Request-Encoding")
+							}
+							return resp, err
+						}
+					},
+				},
+			})
+
+			var got map[string]string
+			err := client.DoRequest(context.Background(), rest.Request{
+				Method:  http.MethodPost,
+				Path:    "/?encoding=" + tt.responseEncoding,
+				Payload: map[string]string{"message": message},
+			}, &got)
+			if err != nil {
+				t.Fatalf("Client.DoRequest() error = %v", err)
+			}
+			if got["message"] != message {
+				t.Errorf("round-trip message mismatch, got %d bytes", len(got["message"]))
+			}
+			if reqEncoding != tt.wantReqEncoding {
+				t.Errorf("request Content-Encoding = %q, want %q", reqEncoding, tt.wantReqEncoding)
+			}
+		})
+	}
+}
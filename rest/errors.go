@@ -1,7 +1,127 @@
 package rest
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 var (
 	ErrAPIError = errors.New("api error")
 )
+
+// APIError is returned when the server responds with a 4xx or 5xx status code.
+//
+// It matches ErrAPIError with errors.Is.
+type APIError struct {
+	StatusCode int         // HTTP status code
+	Header     http.Header // Response headers
+	Body       []byte      // Raw response body
+	RequestID  string      // Request ID returned by the server, or the one sent if the server returned none
+
+	Message string // Error message, if the body is a JSON error response
+	Code    int32  // Error code, if the body is a JSON error response
+	Data    any    // Error context, if the body is a JSON error response
+
+	request *http.Request // Rejected request, used to refresh credentials
+}
+
+// newAPIError creates an APIError from the response status, headers and body.
+// The body is decoded as a JSON error response when possible.
+func newAPIError(statusCode int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       body,
+		RequestID:  "",
+		Message:    "",
+		Code:       0,
+		Data:       nil,
+
+		request: nil,
+	}
+
+	var errResp struct {
+		Message string `json:"message"`
+		Code    int32  `json:"code"`
+		Data    any    `json:"data"`
+	}
+	if json.Unmarshal(body, &errResp) == nil {
+		apiErr.Message = errResp.Message
+		apiErr.Code = errResp.Code
+		apiErr.Data = errResp.Data
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: unexpected status code %d with body %s", ErrAPIError, e.StatusCode, string(e.Body))
+}
+
+func (e *APIError) Unwrap() error {
+	return ErrAPIError
+}
+
+// AsAPIError finds the first APIError in err's chain.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+// HasStatus reports whether err is an APIError with one of the given status codes.
+func HasStatus(err error, statusCodes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+
+	for _, code := range statusCodes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsBadRequest reports whether err is an APIError with status 400 Bad Request.
+func IsBadRequest(err error) bool {
+	return HasStatus(err, http.StatusBadRequest)
+}
+
+// IsUnauthorized reports whether err is an APIError with status 401 Unauthorized.
+func IsUnauthorized(err error) bool {
+	return HasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an APIError with status 403 Forbidden.
+func IsForbidden(err error) bool {
+	return HasStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an APIError with status 404 Not Found.
+func IsNotFound(err error) bool {
+	return HasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError with status 409 Conflict.
+func IsConflict(err error) bool {
+	return HasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is an APIError with status 429 Too Many Requests.
+func IsRateLimited(err error) bool {
+	return HasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsServerError reports whether err is an APIError with a 5xx status code.
+func IsServerError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode >= http.StatusInternalServerError
+}
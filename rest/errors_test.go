@@ -0,0 +1,132 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"too many requests","code":42,"data":{"retry":true}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		}
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{
+		BaseURL: server.URL,
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		wantStatus  int
+		wantMessage string
+		wantCode    int32
+		wantBody    string
+		is          func(error) bool
+	}{
+		{
+			name:        "JSON body",
+			path:        "/json",
+			wantStatus:  http.StatusTooManyRequests,
+			wantMessage: "too many requests",
+			wantCode:    42,
+			wantBody:    `{"message":"too many requests","code":42,"data":{"retry":true}}`,
+			is:          rest.IsRateLimited,
+		},
+		{
+			name:        "Plain text body",
+			path:        "/plain",
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "",
+			wantCode:    0,
+			wantBody:    "not found",
+			is:          rest.IsNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.Do(context.Background(), http.MethodGet, tt.path, nil, nil, nil)
+			if !errors.Is(err, rest.ErrAPIError) {
+				t.Fatalf("expected ErrAPIError, got %v", err)
+			}
+
+			apiErr, ok := rest.AsAPIError(err)
+			if !ok {
+				t.Fatalf("expected *APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.wantStatus)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", apiErr.Code, tt.wantCode)
+			}
+			if string(apiErr.Body) != tt.wantBody {
+				t.Errorf("Body = %q, want %q", apiErr.Body, tt.wantBody)
+			}
+			if !tt.is(err) {
+				t.Errorf("status helper returned false for %v", err)
+			}
+			if rest.IsServerError(err) {
+				t.Errorf("IsServerError() = true for %v", err)
+			}
+		})
+	}
+}
+
+func TestHasStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		codes []int
+		want  bool
+	}{
+		{
+			name:  "Nil error",
+			err:   nil,
+			codes: []int{http.StatusNotFound},
+			want:  false,
+		},
+		{
+			name:  "Other error",
+			err:   errors.New("boom"),
+			codes: []int{http.StatusNotFound},
+			want:  false,
+		},
+		{
+			name:  "Matching status",
+			err:   &rest.APIError{StatusCode: http.StatusBadGateway},
+			codes: []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			want:  true,
+		},
+		{
+			name:  "Wrapped error",
+			err:   fmt.Errorf("failed: %w", &rest.APIError{StatusCode: http.StatusUnauthorized}),
+			codes: []int{http.StatusUnauthorized},
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rest.HasStatus(tt.err, tt.codes...); got != tt.want {
+				t.Errorf("HasStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Redaction configures masking of sensitive data in logs and API errors.
+type Redaction struct {
+	// JSON object keys whose values are masked in logged bodies and API error bodies,
+	// compared case-insensitively.
+	Fields []string
+	// JSON object keys whose values are masked in logged request bodies only, for keys
+	// that carry diagnostics in responses such as the message of API errors.
+	RequestFields []string
+	// Replace the whole body of API errors with a placeholder.
+	ErrorBody bool
+}
+
+// body masks the configured fields in a JSON body. Non-JSON bodies are returned as is.
+func (r Redaction) body(body []byte) []byte {
+	return maskJSON(body, r.Fields)
+}
+
+// requestBody masks the configured fields and request fields in a JSON request body.
+func (r Redaction) requestBody(body []byte) []byte {
+	return maskJSON(body, append(slices.Clip(r.Fields), r.RequestFields...))
+}
+
+// errorBody masks an API error body.
+func (r Redaction) errorBody(body []byte) []byte {
+	if r.ErrorBody && len(body) > 0 {
+		return []byte(redacted)
+	}
+
+	return r.body(body)
+}
+
+// maskJSON masks the values of fields in a JSON body. Non-JSON bodies are returned as is.
+func maskJSON(body []byte, fields []string) []byte {
+	if len(fields) == 0 || len(body) == 0 {
+		return body
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+
+	masked, err := json.Marshal(mask(value, fields))
+	if err != nil {
+		return body
+	}
+
+	return masked
+}
+
+func mask(value any, fields []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isMasked(key, fields) {
+				v[key] = redacted
+			} else {
+				v[key] = mask(item, fields)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = mask(item, fields)
+		}
+	}
+
+	return value
+}
+
+func isMasked(key string, fields []string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactHeaders returns a copy of headers with credentials masked.
+func redactHeaders(headers http.Header) http.Header {
+	clone := headers.Clone()
+	for _, key := range []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"} {
+		if _, ok := clone[key]; ok {
+			clone[key] = []string{redacted}
+		}
+	}
+
+	return clone
+}
+
+// log writes a record if logging is enabled.
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.config.Logger == nil {
+		return
+	}
+
+	if operation := OperationFromContext(ctx); operation != "" {
+		attrs = append(attrs, slog.String("operation", operation))
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	c.config.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
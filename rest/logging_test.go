@@ -0,0 +1,87 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestClient_Do_Logging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"invalid phone","data":{"phoneNumber":"+19162255887"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"123","phoneNumbers":["+19162255887"]}`))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	client := rest.NewClient(rest.Config{
+		BaseURL:   server.URL,
+		Logger:    slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogBodies: true,
+		Redact: rest.Redaction{
+			Fields: []string{"phoneNumbers", "phoneNumber"},
+		},
+	})
+
+	headers := map[string]string{"Authorization": "Basic c2VjcmV0"}
+	payload := map[string]any{"message": "hi", "phoneNumbers": []string{"+19162255887"}}
+
+	ctx := rest.WithOperation(context.Background(), "Send")
+	if err := client.Do(ctx, http.MethodPost, "/", headers, payload, &map[string]any{}); err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+
+	err := client.Do(ctx, http.MethodPost, "/error", headers, payload, nil)
+	if err == nil {
+		t.Fatal("Client.Do() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), "+19162255887") {
+		t.Errorf("error contains phone number: %v", err)
+	}
+	if apiErr, _ := rest.AsAPIError(err); apiErr.Message != "invalid phone" {
+		t.Errorf("APIError.Message = %q, want %q", apiErr.Message, "invalid phone")
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"c2VjcmV0", "+19162255887"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, logs)
+		}
+	}
+	for _, want := range []string{"level=DEBUG", "level=ERROR", "operation=Send", "[REDACTED]"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs don't contain %q:\n%s", want, logs)
+		}
+	}
+}
+
+func TestClient_Do_RedactErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to send to +19162255887"))
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{
+		BaseURL: server.URL,
+		Redact:  rest.Redaction{ErrorBody: true},
+	})
+
+	err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+	if err == nil {
+		t.Fatal("Client.Do() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), "+19162255887") {
+		t.Errorf("error contains error body: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+)
+
+// RoundTripFunc sends a single HTTP request and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc to inspect or modify requests and responses.
+//
+// Middlewares are invoked for every attempt, including retries. The logical
+// operation name is available via OperationFromContext(req.Context()).
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx carrying the logical operation name,
+// such as "Send" or "PostCSR".
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// OperationFromContext returns the logical operation name stored in ctx,
+// or an empty string if there is none.
+func OperationFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
+
+// chain wraps next with middlewares, the first middleware being the outermost.
+func chain(next RoundTripFunc, middlewares []Middleware) RoundTripFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+
+	return next
+}
@@ -0,0 +1,66 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestClient_Do_Middlewares(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") != "acme" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var calls []string
+	var gotOperation string
+	var gotStatus int
+
+	client := rest.NewClient(rest.Config{
+		BaseURL: server.URL,
+		Middlewares: []rest.Middleware{
+			func(next rest.RoundTripFunc) rest.RoundTripFunc {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, "outer")
+					gotOperation = rest.OperationFromContext(req.Context())
+
+					resp, err := next(req)
+					if resp != nil {
+						gotStatus = resp.StatusCode
+					}
+					return resp, err
+				}
+			},
+			func(next rest.RoundTripFunc) rest.RoundTripFunc {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, "inner")
+					req.Header.Set("X-Tenant", "acme")
+					return next(req)
+				}
+			},
+		},
+	})
+
+	ctx := rest.WithOperation(context.Background(), "Test")
+	if err := client.Do(ctx, http.MethodGet, "/", nil, nil, nil); err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+
+	if want := []string{"outer", "inner"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("middleware calls = %v, want %v", calls, want)
+	}
+	if gotOperation != "Test" {
+		t.Errorf("operation = %q, want %q", gotOperation, "Test")
+	}
+	if gotStatus != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", gotStatus, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Option configures a Client. The same options are accepted by the clients
+// of the API packages.
+type Option func(*Config)
+
+// NewConfig applies options to an empty Config.
+func NewConfig(options ...Option) Config {
+	var config Config
+	config.Apply(options...)
+
+	return config
+}
+
+// Apply applies options to the config in order.
+func (c *Config) Apply(options ...Option) {
+	for _, option := range options {
+		option(c)
+	}
+}
+
+// WithClient sets the HTTP client.
+func WithClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.Client = client
+	}
+}
+
+// WithTransport tunes timeouts and connection pooling of the default HTTP
+// client. It is ignored if a client is set with WithClient.
+func WithTransport(config TransportConfig) Option {
+	return func(c *Config) {
+		c.Transport = config
+	}
+}
+
+// WithBaseURL sets the base URL of the API.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithRetryPolicy sets the retry policy.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Config) {
+		c.Retry = policy
+	}
+}
+
+// WithAuthenticator sets the authenticator invoked for every attempt.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(c *Config) {
+		c.Authenticator = authenticator
+	}
+}
+
+// WithTLS trusts additional root CAs, pins server keys and sets client
+// certificates for mutual TLS.
+func WithTLS(config TLSConfig) Option {
+	return func(c *Config) {
+		c.TLS = config
+	}
+}
+
+// WithCircuitBreaker stops sending requests while the server is failing.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *Config) {
+		c.CircuitBreaker = breaker
+	}
+}
+
+// WithCache caches GET responses.
+func WithCache(cache *Cache) Option {
+	return func(c *Config) {
+		c.Cache = cache
+	}
+}
+
+// WithMiddleware appends middlewares to the chain around every HTTP request.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Config) {
+		c.Middlewares = append(c.Middlewares, middlewares...)
+	}
+}
+
+// WithCompression compresses request bodies.
+func WithCompression(compression Compression) Option {
+	return func(c *Config) {
+		c.Compression = compression
+	}
+}
+
+// WithCoalescing shares a single in-flight request between concurrent
+// identical GET requests.
+func WithCoalescing() Option {
+	return func(c *Config) {
+		c.CoalesceRequests = true
+	}
+}
+
+// WithMaxResponseSize limits the size of response bodies, negative means no limit.
+func WithMaxResponseSize(size int64) Option {
+	return func(c *Config) {
+		c.MaxResponseSize = size
+	}
+}
+
+// WithLogger enables logging of requests at debug level, retries at warn level
+// and failures at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithLogBodies logs request and response bodies at debug level.
+func WithLogBodies() Option {
+	return func(c *Config) {
+		c.LogBodies = true
+	}
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of outgoing requests.
+type RateLimiter interface {
+	// Wait blocks until a request is allowed or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a RateLimiter that refills at a fixed rate and allows bursts
+// up to its capacity. It is safe for concurrent use.
+type TokenBucket struct {
+	mu sync.Mutex
+
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a full bucket that allows rate requests per second
+// with bursts of up to burst requests. A burst below 1 is treated as 1.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	capacity := float64(max(burst, 1))
+
+	return &TokenBucket{
+		mu:     sync.Mutex{},
+		rate:   rate,
+		burst:  capacity,
+		tokens: capacity,
+		last:   time.Now(),
+	}
+}
+
+// Wait takes a token from the bucket, blocking until one is available or
+// ctx is done. The token is returned to the bucket if ctx is done first.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := Sleep(ctx, delay); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// reserve takes a token, possibly going into debt, and returns how long
+// the caller has to wait until the debt is repaid.
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.rate > 0 {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	if b.rate <= 0 {
+		// never refills, wait until ctx is done
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token.
+func (b *TokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.burst, b.tokens+1)
+}
@@ -0,0 +1,40 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestTokenBucket_Wait(t *testing.T) {
+	bucket := rest.NewTokenBucket(20, 2)
+
+	start := time.Now()
+	for range 4 {
+		if err := bucket.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	// 2 tokens are available immediately, the next 2 take 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Wait() did not block, elapsed %v", elapsed)
+	}
+}
+
+func TestTokenBucket_WaitCanceled(t *testing.T) {
+	bucket := rest.NewTokenBucket(0.1, 1)
+	if err := bucket.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := bucket.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
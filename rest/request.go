@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Request describes an API request.
+type Request struct {
+	Method  string            // HTTP method
+	Path    string            // Path relative to the base URL
+	Query   url.Values        // Optional query parameters
+	Headers map[string]string // Optional request headers
+	Payload any               // Optional payload, encoded as JSON
+	Options []CallOption      // Optional per-call options
+}
+
+// url returns the request URL for the given base URL.
+func (r Request) url(baseURL string) string {
+	u := baseURL + r.Path
+	if len(r.Query) == 0 {
+		return u
+	}
+
+	if strings.Contains(r.Path, "?") {
+		return u + "&" + r.Query.Encode()
+	}
+
+	return u + "?" + r.Query.Encode()
+}
+
+// key identifies requests to the base URL that can share a response:
+// payloads are not included.
+func (r Request) key(baseURL string) string {
+	var b strings.Builder
+	b.WriteString(r.Method + " " + r.url(baseURL))
+
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		b.WriteString("\n" + name + ": " + r.Headers[name])
+	}
+
+	return b.String()
+}
+
+// Do sends the request and decodes the JSON response into a value of type T.
+func Do[T any](ctx context.Context, c *Client, req Request) (T, error) {
+	resp := new(T)
+	err := c.DoRequest(ctx, req, resp)
+
+	return *resp, err
+}
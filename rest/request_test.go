@@ -0,0 +1,72 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"query":"` + r.URL.RawQuery + `","header":"` + r.Header.Get("X-Test") + `"}`))
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{
+		BaseURL: server.URL,
+	})
+
+	type response struct {
+		Query  string `json:"query"`
+		Header string `json:"header"`
+	}
+
+	tests := []struct {
+		name string
+		req  rest.Request
+		want response
+	}{
+		{
+			name: "Without query",
+			req: rest.Request{
+				Method: http.MethodGet,
+				Path:   "/logs",
+			},
+			want: response{Query: "", Header: ""},
+		},
+		{
+			name: "With query and headers",
+			req: rest.Request{
+				Method:  http.MethodGet,
+				Path:    "/logs",
+				Query:   url.Values{"from": {"2024-01-01T00:00:00Z"}, "to": {"2024-01-02T00:00:00Z"}},
+				Headers: map[string]string{"X-Test": "yes"},
+			},
+			want: response{Query: "from=2024-01-01T00%3A00%3A00Z&to=2024-01-02T00%3A00%3A00Z", Header: "yes"},
+		},
+		{
+			name: "Query appended to path query",
+			req: rest.Request{
+				Method: http.MethodGet,
+				Path:   "/logs?limit=10",
+				Query:  url.Values{"offset": {"20"}},
+			},
+			want: response{Query: "limit=10&offset=20", Header: ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rest.Do[response](context.Background(), client, tt.req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Do() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
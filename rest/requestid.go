@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is the header carrying the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying a caller-chosen request ID,
+// which is sent with every request made with the context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or an empty
+// string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID generates a random request ID in the UUID v4 format.
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40 //nolint:mnd // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 //nolint:mnd // RFC 4122 variant
+
+	buf := make([]byte, 36) //nolint:mnd // UUID length
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+
+	return string(buf)
+}
+
+// ensureRequestID returns ctx with a request ID, generating one if needed.
+func ensureRequestID(ctx context.Context) context.Context {
+	if RequestIDFromContext(ctx) != "" {
+		return ctx
+	}
+
+	return WithRequestID(ctx, NewRequestID())
+}
+
+// ResponseInfo describes the last response received for a call.
+type ResponseInfo struct {
+	StatusCode int         // HTTP status code
+	Header     http.Header // Response headers
+	RequestID  string      // Request ID returned by the server, or the one sent if the server returned none
+}
+
+type responseInfoKey struct{}
+
+// CaptureResponse returns a copy of ctx that makes calls store the details of
+// their last response in info. Calls made with the context must not run concurrently.
+func CaptureResponse(ctx context.Context, info *ResponseInfo) context.Context {
+	return context.WithValue(ctx, responseInfoKey{}, info)
+}
+
+// responseRequestID returns the request ID of the response, falling back to
+// the ID of the request.
+func responseRequestID(req *http.Request, resp *http.Response) string {
+	if id := resp.Header.Get(RequestIDHeader); id != "" {
+		return id
+	}
+
+	return req.Header.Get(RequestIDHeader)
+}
+
+// captureResponse stores the response details in the ResponseInfo of ctx, if any.
+func captureResponse(ctx context.Context, req *http.Request, resp *http.Response) {
+	info, ok := ctx.Value(responseInfoKey{}).(*ResponseInfo)
+	if !ok || info == nil {
+		return
+	}
+
+	*info = ResponseInfo{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		RequestID:  responseRequestID(req, resp),
+	}
+}
@@ -0,0 +1,144 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestNewRequestID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, second := rest.NewRequestID(), rest.NewRequestID()
+	if !pattern.MatchString(first) {
+		t.Errorf("NewRequestID() = %q, want UUID v4", first)
+	}
+	if first == second {
+		t.Errorf("NewRequestID() returned %q twice", first)
+	}
+}
+
+func TestClient_Do_RequestID(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctxID         string
+		serverID      string
+		status        int
+		wantRequestID func(sent string) string
+	}{
+		{
+			name:          "Generated",
+			status:        http.StatusNoContent,
+			wantRequestID: func(sent string) string { return sent },
+		},
+		{
+			name:          "From context",
+			ctxID:         "caller-id",
+			status:        http.StatusNoContent,
+			wantRequestID: func(string) string { return "caller-id" },
+		},
+		{
+			name:          "From server",
+			ctxID:         "caller-id",
+			serverID:      "server-id",
+			status:        http.StatusNoContent,
+			wantRequestID: func(string) string { return "server-id" },
+		},
+		{
+			name:          "Error from server",
+			serverID:      "server-id",
+			status:        http.StatusBadRequest,
+			wantRequestID: func(string) string { return "server-id" },
+		},
+		{
+			name:          "Error",
+			ctxID:         "caller-id",
+			status:        http.StatusBadRequest,
+			wantRequestID: func(string) string { return "caller-id" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var sent []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				sent = append(sent, r.Header.Get(rest.RequestIDHeader))
+				mu.Unlock()
+
+				if tt.serverID != "" {
+					w.Header().Set(rest.RequestIDHeader, tt.serverID)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := rest.NewClient(rest.Config{BaseURL: server.URL, Retry: rest.NoRetry()})
+
+			ctx := context.Background()
+			if tt.ctxID != "" {
+				ctx = rest.WithRequestID(ctx, tt.ctxID)
+			}
+			var info rest.ResponseInfo
+			ctx = rest.CaptureResponse(ctx, &info)
+
+			err := client.Do(ctx, http.MethodGet, "/", nil, nil, nil)
+
+			if len(sent) != 1 || sent[0] == "" {
+				t.Fatalf("sent request IDs = %v, want one", sent)
+			}
+			if tt.ctxID != "" && sent[0] != tt.ctxID {
+				t.Errorf("sent request ID = %q, want %q", sent[0], tt.ctxID)
+			}
+
+			want := tt.wantRequestID(sent[0])
+			if info.RequestID != want || info.StatusCode != tt.status {
+				t.Errorf("ResponseInfo = %+v, want request ID %q and status %d", info, want, tt.status)
+			}
+
+			if tt.status < http.StatusBadRequest {
+				if err != nil {
+					t.Fatalf("Client.Do() error = %v", err)
+				}
+				return
+			}
+
+			apiErr, ok := rest.AsAPIError(err)
+			if !ok {
+				t.Fatalf("Client.Do() error = %v, want APIError", err)
+			}
+			if apiErr.RequestID != want {
+				t.Errorf("APIError.RequestID = %q, want %q", apiErr.RequestID, want)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RequestIDRetries(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(rest.RequestIDHeader))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{BaseURL: server.URL, Retry: fastRetryPolicy(3)})
+
+	_ = client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+	if len(sent) != 3 {
+		t.Fatalf("requests = %d, want 3", len(sent))
+	}
+	for _, id := range sent[1:] {
+		if id != sent[0] {
+			t.Errorf("request IDs of retries = %v, want all equal", sent)
+			break
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package resttest provides HTTP transports for testing API clients.
+//
+// The Recorder records interactions with a real server to a JSON cassette and
+// replays them later without network access. Credentials and phone numbers
+// are scrubbed before interactions are stored.
+//
+// The FaultTransport injects latency, dropped connections, error responses
+// and corrupted bodies with seedable randomness.
+package resttest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"unicode/utf8"
+)
+
+// Cassette is a recorded sequence of HTTP interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a scrubbed HTTP request.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is a scrubbed HTTP response.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is stored as a string if it is valid UTF-8 and as base64 otherwise.
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b)) //nolint:wrapcheck // marshaler
+	}
+
+	return json.Marshal(map[string]string{ //nolint:wrapcheck // marshaler
+		"base64": base64.StdEncoding.EncodeToString(b),
+	})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+	*b = decoded
+
+	return nil
+}
+
+// LoadCassette reads a cassette from a JSON file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	cassette := &Cassette{Interactions: nil}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %w", err)
+	}
+
+	return cassette, nil
+}
+
+// Save writes the cassette to a JSON file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil { //nolint:mnd // file mode
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package resttest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrConnectionDropped = errors.New("injected fault: connection dropped")
+
+// DefaultMalformedBody is a message state with fields of wrong types.
+const DefaultMalformedBody = `{"id":42,"state":["Pending"],"recipients":"+10000000000","isHashed":"no"}`
+
+// Fault is a kind of injected fault.
+type Fault string
+
+const (
+	FaultLatency      Fault = "latency"
+	FaultDrop         Fault = "drop"
+	FaultDropResponse Fault = "drop_response"
+	FaultRateLimit    Fault = "rate_limit"
+	FaultServerError  Fault = "server_error"
+	FaultTruncate     Fault = "truncate"
+	FaultMalformed    Fault = "malformed"
+)
+
+// Faults configures the FaultTransport. Rates are probabilities from 0 to 1
+// evaluated independently for every request.
+type Faults struct {
+	// Seed of the random generator. Runs with the same seed and the same
+	// sequence of requests inject the same faults.
+	Seed uint64
+
+	Latency       time.Duration // Delay added to every request
+	LatencyJitter time.Duration // Optional random delay of up to this duration added to Latency
+
+	DropRate         float64 // Rate of connections dropped before the request is sent
+	DropResponseRate float64 // Rate of connections dropped after the server has processed the request
+
+	RateLimitRate   float64       // Rate of 429 Too Many Requests responses
+	RetryAfter      time.Duration // Optional Retry-After of 429 responses, rounded down to seconds
+	ServerErrorRate float64       // Rate of 500, 502, 503 and 504 responses
+
+	TruncateRate  float64 // Rate of successful response bodies truncated at a random offset
+	MalformedRate float64 // Rate of successful response bodies replaced with MalformedBody
+	MalformedBody string  // Optional malformed body, defaults to DefaultMalformedBody
+}
+
+// FaultTransport is an http.RoundTripper that injects faults in front of
+// another transport. It is safe for concurrent use, but faults are only
+// reproducible for a deterministic order of requests.
+type FaultTransport struct {
+	next   http.RoundTripper
+	faults Faults
+
+	mu       sync.Mutex
+	rnd      *rand.Rand
+	injected map[Fault]int
+}
+
+// NewFaultTransport wraps next, defaults to `http.DefaultTransport`.
+func NewFaultTransport(next http.RoundTripper, faults Faults) *FaultTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if faults.MalformedBody == "" {
+		faults.MalformedBody = DefaultMalformedBody
+	}
+
+	return &FaultTransport{
+		next:   next,
+		faults: faults,
+
+		mu:       sync.Mutex{},
+		rnd:      rand.New(rand.NewPCG(faults.Seed, faults.Seed)), //nolint:gosec // reproducibility is the point
+		injected: map[Fault]int{},
+	}
+}
+
+// Client returns an HTTP client using the FaultTransport.
+func (t *FaultTransport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Injected returns the number of injected faults of each kind.
+func (t *FaultTransport) Injected() map[Fault]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	injected := make(map[Fault]int, len(t.injected))
+	for k, v := range t.injected {
+		injected[k] = v
+	}
+
+	return injected
+}
+
+// plan is the fault drawn for a single request.
+type plan struct {
+	latency time.Duration
+	fault   Fault
+	status  int     // status of FaultRateLimit and FaultServerError
+	keep    float64 // fraction of the body kept by FaultTruncate
+}
+
+// draw decides the faults of the next request.
+func (t *FaultTransport) draw() plan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	serverErrors := []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+
+	// all values are drawn for every request so that the sequence doesn't depend on the outcome
+	var (
+		jitter       = t.rnd.Float64()
+		drop         = t.rnd.Float64() < t.faults.DropRate
+		rateLimit    = t.rnd.Float64() < t.faults.RateLimitRate
+		serverError  = t.rnd.Float64() < t.faults.ServerErrorRate
+		status       = serverErrors[t.rnd.IntN(len(serverErrors))]
+		dropResponse = t.rnd.Float64() < t.faults.DropResponseRate
+		truncate     = t.rnd.Float64() < t.faults.TruncateRate
+		keep         = t.rnd.Float64()
+		malformed    = t.rnd.Float64() < t.faults.MalformedRate
+	)
+
+	p := plan{
+		latency: t.faults.Latency + time.Duration(jitter*float64(t.faults.LatencyJitter)),
+		fault:   "",
+		status:  0,
+		keep:    keep,
+	}
+	if p.latency > 0 {
+		t.injected[FaultLatency]++
+	}
+
+	switch {
+	case drop:
+		p.fault = FaultDrop
+	case rateLimit:
+		p.fault, p.status = FaultRateLimit, http.StatusTooManyRequests
+	case serverError:
+		p.fault, p.status = FaultServerError, status
+	case dropResponse:
+		p.fault = FaultDropResponse
+	case truncate:
+		p.fault = FaultTruncate
+	case malformed:
+		p.fault = FaultMalformed
+	}
+
+	return p
+}
+
+// count records an injected fault.
+func (t *FaultTransport) count(fault Fault) {
+	t.mu.Lock()
+	t.injected[fault]++
+	t.mu.Unlock()
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p := t.draw()
+
+	if p.latency > 0 {
+		timer := time.NewTimer(p.latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err() //nolint:wrapcheck // transport errors are wrapped by http.Client
+		case <-timer.C:
+		}
+	}
+
+	switch p.fault {
+	case FaultDrop:
+		t.count(p.fault)
+		closeBody(req)
+		return nil, ErrConnectionDropped
+	case FaultRateLimit, FaultServerError:
+		t.count(p.fault)
+		closeBody(req)
+		return t.respond(req, p.status), nil
+	default:
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transport errors are wrapped by http.Client
+	}
+
+	switch p.fault {
+	case FaultDropResponse:
+		resp.Body.Close()
+		t.count(p.fault)
+		return nil, ErrConnectionDropped
+	case FaultTruncate, FaultMalformed:
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			// only successful responses are corrupted
+			return resp, nil
+		}
+
+		err := t.replaceBody(resp, func(body []byte) []byte {
+			if p.fault == FaultMalformed {
+				return []byte(t.faults.MalformedBody)
+			}
+			return body[:int(p.keep*float64(len(body)))]
+		})
+		if err != nil {
+			// a RoundTripper returns either a response or an error
+			return nil, err
+		}
+
+		t.count(p.fault)
+		return resp, nil
+	default:
+		return resp, nil
+	}
+}
+
+// respond creates a synthetic error response.
+func (t *FaultTransport) respond(req *http.Request, status int) *http.Response {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	if status == http.StatusTooManyRequests && t.faults.RetryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(t.faults.RetryAfter.Seconds())))
+	}
+
+	body := fmt.Sprintf(`{"message":"injected fault: %s"}`, http.StatusText(status))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// replaceBody reads the decoded response body and replaces it with the result of fn.
+func (t *FaultTransport) replaceBody(resp *http.Response, fn func([]byte) []byte) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		if body, err = gunzip(body); err != nil {
+			return err
+		}
+		resp.Header.Del("Content-Encoding")
+	}
+
+	body = fn(body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+
+	return nil
+}
+
+// closeBody closes the request body of requests that aren't sent, as
+// required from http.RoundTripper implementations.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
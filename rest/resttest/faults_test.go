@@ -0,0 +1,176 @@
+package resttest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/rest/resttest"
+)
+
+func newStateServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"123","recipients":[{"phoneNumber":"+79990001234"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFaultTransport(t *testing.T) {
+	server := newStateServer(t)
+
+	tests := []struct {
+		name      string
+		faults    resttest.Faults
+		wantErr   func(error) bool
+		wantFault resttest.Fault
+	}{
+		{
+			name:      "Drop",
+			faults:    resttest.Faults{DropRate: 1},
+			wantErr:   func(err error) bool { return errors.Is(err, resttest.ErrConnectionDropped) },
+			wantFault: resttest.FaultDrop,
+		},
+		{
+			name:      "Drop response",
+			faults:    resttest.Faults{DropResponseRate: 1},
+			wantErr:   func(err error) bool { return errors.Is(err, resttest.ErrConnectionDropped) },
+			wantFault: resttest.FaultDropResponse,
+		},
+		{
+			name:   "Rate limit",
+			faults: resttest.Faults{RateLimitRate: 1, RetryAfter: 2 * time.Second},
+			wantErr: func(err error) bool {
+				apiErr, ok := rest.AsAPIError(err)
+				return ok && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.Header.Get("Retry-After") == "2"
+			},
+			wantFault: resttest.FaultRateLimit,
+		},
+		{
+			name:      "Server error",
+			faults:    resttest.Faults{ServerErrorRate: 1},
+			wantErr:   rest.IsServerError,
+			wantFault: resttest.FaultServerError,
+		},
+		{
+			name:      "Truncate",
+			faults:    resttest.Faults{TruncateRate: 1},
+			wantErr:   func(err error) bool { return err != nil && !rest.HasStatus(err) },
+			wantFault: resttest.FaultTruncate,
+		},
+		{
+			name:      "Malformed",
+			faults:    resttest.Faults{MalformedRate: 1},
+			wantErr:   func(err error) bool { return err != nil && !rest.HasStatus(err) },
+			wantFault: resttest.FaultMalformed,
+		},
+		{
+			name:      "Latency",
+			faults:    resttest.Faults{Latency: 20 * time.Millisecond},
+			wantErr:   func(err error) bool { return err == nil },
+			wantFault: resttest.FaultLatency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := resttest.NewFaultTransport(nil, tt.faults)
+			client := rest.NewClient(rest.Config{
+				Client:  transport.Client(),
+				BaseURL: server.URL,
+				Retry:   rest.NoRetry(),
+			})
+
+			start := time.Now()
+			_, err := rest.Do[state](context.Background(), client, rest.Request{Method: http.MethodGet, Path: "/message/123"})
+			if !tt.wantErr(err) {
+				t.Errorf("Do() unexpected error = %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.faults.Latency {
+				t.Errorf("Do() took %v, want at least %v", elapsed, tt.faults.Latency)
+			}
+			if got := transport.Injected(); got[tt.wantFault] != 1 {
+				t.Errorf("Injected() = %v, want one %s", got, tt.wantFault)
+			}
+		})
+	}
+}
+
+func TestFaultTransport_Seed(t *testing.T) {
+	server := newStateServer(t)
+
+	run := func(seed uint64) []string {
+		transport := resttest.NewFaultTransport(nil, resttest.Faults{
+			Seed:            seed,
+			DropRate:        0.1,
+			RateLimitRate:   0.1,
+			ServerErrorRate: 0.2,
+			TruncateRate:    0.1,
+			MalformedRate:   0.1,
+		})
+		client := rest.NewClient(rest.Config{
+			Client:  transport.Client(),
+			BaseURL: server.URL,
+			Retry:   rest.NoRetry(),
+		})
+
+		outcomes := make([]string, 0, 50)
+		for range 50 {
+			resp, err := rest.Do[state](context.Background(), client, rest.Request{Method: http.MethodGet, Path: "/message/123"})
+			if apiErr, ok := rest.AsAPIError(err); ok {
+				outcomes = append(outcomes, fmt.Sprint(apiErr.StatusCode))
+			} else {
+				outcomes = append(outcomes, fmt.Sprint(resp, err != nil))
+			}
+		}
+
+		return outcomes
+	}
+
+	first := run(42)
+	if second := run(42); !reflect.DeepEqual(first, second) {
+		t.Errorf("outcomes with the same seed differ:\n%v\n%v", first, second)
+	}
+	if other := run(7); reflect.DeepEqual(first, other) {
+		t.Errorf("outcomes with different seeds are equal: %v", first)
+	}
+}
+
+type failingBody struct{}
+
+func (failingBody) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+func (failingBody) Close() error             { return nil }
+
+type failingBodyTransport struct{}
+
+func (failingBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       failingBody{},
+		Request:    req,
+	}, nil
+}
+
+func TestFaultTransport_ReadError(t *testing.T) {
+	transport := resttest.NewFaultTransport(failingBodyTransport{}, resttest.Faults{MalformedRate: 1})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+	resp, err := transport.RoundTrip(req)
+	if resp != nil || err == nil {
+		t.Errorf("RoundTrip() = %v, %v, want nil response and error", resp, err)
+	}
+	if got := transport.Injected()[resttest.FaultMalformed]; got != 0 {
+		t.Errorf("Injected()[%q] = %d, want 0", resttest.FaultMalformed, got)
+	}
+}
@@ -0,0 +1,309 @@
+package resttest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+var ErrInteractionNotFound = errors.New("no matching interaction in cassette")
+
+// Mode selects whether the Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette without network access.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the transport and records the interactions.
+	ModeRecord
+)
+
+// Matcher reports whether a request matches a recorded one. The body is the
+// decoded and scrubbed request body.
+type Matcher func(req *http.Request, body []byte, recorded RecordedRequest) bool
+
+// MatchMethod matches requests by HTTP method.
+func MatchMethod(req *http.Request, _ []byte, recorded RecordedRequest) bool {
+	return req.Method == recorded.Method
+}
+
+// MatchPath matches requests by URL path.
+func MatchPath(req *http.Request, _ []byte, recorded RecordedRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return req.URL.Path == u.Path
+}
+
+// MatchQuery matches requests by URL query parameters.
+func MatchQuery(req *http.Request, _ []byte, recorded RecordedRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return req.URL.Query().Encode() == u.Query().Encode()
+}
+
+// MatchBody matches requests by body. JSON bodies are compared semantically.
+func MatchBody(_ *http.Request, body []byte, recorded RecordedRequest) bool {
+	var got, want any
+	if json.Unmarshal(body, &got) == nil && json.Unmarshal(recorded.Body, &want) == nil {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		return bytes.Equal(gotJSON, wantJSON)
+	}
+
+	return bytes.Equal(body, recorded.Body)
+}
+
+type Option func(*Recorder)
+
+// WithTransport sets the transport used in record mode, defaults to `http.DefaultTransport`.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithMatchers replaces the default matchers on method, path and body.
+func WithMatchers(matchers ...Matcher) Option {
+	return func(r *Recorder) {
+		r.matchers = matchers
+	}
+}
+
+// WithScrubbers adds scrubbers applied to request and response bodies in
+// addition to ScrubPhoneNumbers, such as ScrubPhoneNumberPattern.
+func WithScrubbers(scrubbers ...Scrubber) Option {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, scrubbers...)
+	}
+}
+
+// Recorder is an http.RoundTripper that records interactions to a cassette
+// or replays them from it. It is safe for concurrent use.
+//
+// Recorded interactions never contain Authorization, Cookie or Set-Cookie
+// headers, and phone numbers in bodies are replaced with ScrubbedPhoneNumber.
+// Incoming requests are scrubbed the same way before matching.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	matchers  []Matcher
+	scrubbers []Scrubber
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewRecorder creates a Recorder for the cassette at path. In replay mode the
+// cassette is loaded immediately; in record mode it is written by Save.
+func NewRecorder(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		matchers:  []Matcher{MatchMethod, MatchPath, MatchBody},
+		scrubbers: []Scrubber{ScrubPhoneNumbers},
+
+		mu:       sync.Mutex{},
+		cassette: &Cassette{Interactions: nil},
+		used:     nil,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.used = make([]bool, len(cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Client returns an HTTP client using the Recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Save writes the recorded interactions to the cassette. It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, r.scrub(body))
+	}
+
+	return r.record(req, body)
+}
+
+// replay answers the request with the first unused matching interaction.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(req, body, interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		resp := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode:    resp.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        cloneHeader(resp.Headers),
+			Body:          io.NopCloser(bytes.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, req.URL)
+}
+
+func (r *Recorder) matches(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	for _, match := range r.matchers {
+		if !match(req, body, recorded) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// record forwards the request and stores the scrubbed interaction.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	forwarded := req.Clone(req.Context())
+	if req.Body != nil {
+		forwarded.Body = io.NopCloser(bytes.NewReader(body))
+		forwarded.ContentLength = int64(len(body))
+		forwarded.Header.Del("Content-Encoding")
+	}
+	// let the transport negotiate compression so that bodies are stored in plain text
+	forwarded.Header.Del("Accept-Encoding")
+
+	resp, err := r.transport.RoundTrip(forwarded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to forward request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		if respBody, err = gunzip(respBody); err != nil {
+			return nil, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = int64(len(respBody))
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: scrubHeaders(forwarded.Header),
+			Body:    r.scrub(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    scrubHeaders(resp.Header),
+			Body:       r.scrub(respBody),
+		},
+	})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	return resp, nil
+}
+
+func (r *Recorder) scrub(body []byte) []byte {
+	for _, scrub := range r.scrubbers {
+		body = scrub(body)
+	}
+
+	return body
+}
+
+// requestBody reads and restores the request body, decompressing gzip bodies.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		return gunzip(body)
+	}
+
+	return body, nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress body: %w", err)
+	}
+	defer zr.Close()
+
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress body: %w", err)
+	}
+
+	return decoded, nil
+}
+
+func cloneHeader(header http.Header) http.Header {
+	if header == nil {
+		return http.Header{}
+	}
+
+	return header.Clone()
+}
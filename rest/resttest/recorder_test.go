@@ -0,0 +1,184 @@
+package resttest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/rest/resttest"
+)
+
+type message struct {
+	Message      string   `json:"message"`
+	PhoneNumbers []string `json:"phoneNumbers"`
+}
+
+type recipient struct {
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+type state struct {
+	ID         string      `json:"id"`
+	Recipients []recipient `json:"recipients"`
+}
+
+func newClient(baseURL string, httpClient *http.Client) *rest.Client {
+	return rest.NewClient(rest.Config{
+		Client:        httpClient,
+		BaseURL:       baseURL,
+		Retry:         rest.NoRetry(),
+		Authenticator: rest.BasicAuth("user", "secret"),
+		Compression:   rest.Compression{RequestThreshold: 1},
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "user" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "+79990001234") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"123","recipients":[{"phoneNumber":"+79990001234"}]}`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "send.json")
+	req := rest.Request{
+		Method:  http.MethodPost,
+		Path:    "/message",
+		Payload: message{Message: "Hello", PhoneNumbers: []string{"+79990001234"}},
+	}
+
+	recorder, err := resttest.NewRecorder(path, resttest.ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	recorded, err := rest.Do[state](context.Background(), newClient(server.URL, recorder.Client()), req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if recorded.ID != "123" || recorded.Recipients[0].PhoneNumber != "+79990001234" {
+		t.Errorf("Do() = %+v, want live response", recorded)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	server.Close()
+
+	cassette, _ := os.ReadFile(path)
+	for _, secret := range []string{"Authorization", "+79990001234"} {
+		if strings.Contains(string(cassette), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, cassette)
+		}
+	}
+
+	player, err := resttest.NewRecorder(path, resttest.ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	client := newClient(server.URL, player.Client())
+
+	replayed, err := rest.Do[state](context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("Do() replay error = %v", err)
+	}
+	if replayed.ID != "123" || replayed.Recipients[0].PhoneNumber != resttest.ScrubbedPhoneNumber {
+		t.Errorf("Do() replay = %+v, want scrubbed response", replayed)
+	}
+
+	// interactions are replayed once
+	if _, err := rest.Do[state](context.Background(), client, req); !errors.Is(err, resttest.ErrInteractionNotFound) {
+		t.Errorf("Do() second replay error = %v, want %v", err, resttest.ErrInteractionNotFound)
+	}
+}
+
+func TestRecorder_Matchers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := &resttest.Cassette{
+		Interactions: []resttest.Interaction{
+			{
+				Request:  resttest.RecordedRequest{Method: http.MethodPost, URL: "http://localhost/message", Body: resttest.Body(`{"message":"a"}`)},
+				Response: resttest.RecordedResponse{StatusCode: http.StatusCreated, Body: resttest.Body(`{"id":"a"}`)},
+			},
+			{
+				Request:  resttest.RecordedRequest{Method: http.MethodPost, URL: "http://localhost/message", Body: resttest.Body(`{"message":"b"}`)},
+				Response: resttest.RecordedResponse{StatusCode: http.StatusCreated, Body: resttest.Body(`{"id":"b"}`)},
+			},
+			{
+				Request:  resttest.RecordedRequest{Method: http.MethodGet, URL: "http://localhost/message/b"},
+				Response: resttest.RecordedResponse{StatusCode: http.StatusOK, Body: resttest.Body(`{"id":"b"}`)},
+			},
+		},
+	}
+	if err := cassette.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     rest.Request
+		matcher []resttest.Matcher
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Body",
+			req:  rest.Request{Method: http.MethodPost, Path: "/message", Payload: map[string]string{"message": "b"}},
+			want: "b",
+		},
+		{
+			name: "Path",
+			req:  rest.Request{Method: http.MethodGet, Path: "/message/b"},
+			want: "b",
+		},
+		{
+			name:    "Method",
+			req:     rest.Request{Method: http.MethodDelete, Path: "/message/b"},
+			wantErr: resttest.ErrInteractionNotFound,
+		},
+		{
+			name:    "Without body matcher",
+			req:     rest.Request{Method: http.MethodPost, Path: "/message", Payload: map[string]string{"message": "b"}},
+			matcher: []resttest.Matcher{resttest.MatchMethod, resttest.MatchPath},
+			want:    "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []resttest.Option
+			if tt.matcher != nil {
+				opts = append(opts, resttest.WithMatchers(tt.matcher...))
+			}
+
+			player, err := resttest.NewRecorder(path, resttest.ModeReplay, opts...)
+			if err != nil {
+				t.Fatalf("NewRecorder() error = %v", err)
+			}
+
+			got, err := rest.Do[state](context.Background(), newClient("http://localhost", player.Client()), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.ID != tt.want {
+				t.Errorf("Do() ID = %q, want %q", got.ID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package resttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ScrubbedPhoneNumber replaces phone numbers in recorded bodies.
+const ScrubbedPhoneNumber = "+10000000000"
+
+// phoneNumberPattern matches JSON strings consisting of a phone number.
+var phoneNumberPattern = regexp.MustCompile(`"\+?[0-9]{10,15}"`)
+
+// Scrubber masks sensitive data of a body before it is stored or matched.
+type Scrubber func(body []byte) []byte
+
+// ScrubPhoneNumbers replaces the values of the `phoneNumbers` and `phoneNumber`
+// JSON keys with ScrubbedPhoneNumber, whatever their format. Bodies without
+// phone numbers and non-JSON bodies are returned as is.
+func ScrubPhoneNumbers(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// numbers are kept as recorded
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil || dec.More() {
+		return body
+	}
+	if !scrubPhoneNumbers(value) {
+		return body
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return body
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// ScrubPhoneNumberPattern replaces any JSON string of 10 to 15 digits with an
+// optional leading plus with ScrubbedPhoneNumber. It catches phone numbers
+// outside the known keys, but also replaces IDs and timestamps of that length,
+// so it is only applied if added with WithScrubbers.
+func ScrubPhoneNumberPattern(body []byte) []byte {
+	return phoneNumberPattern.ReplaceAll(body, []byte(`"`+ScrubbedPhoneNumber+`"`))
+}
+
+// scrubPhoneNumbers replaces phone numbers in a decoded JSON value and reports
+// whether any were found.
+func scrubPhoneNumbers(value any) bool {
+	scrubbed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if strings.EqualFold(key, "phoneNumbers") || strings.EqualFold(key, "phoneNumber") {
+				v[key] = scrubPhoneNumber(item)
+				scrubbed = true
+			} else if scrubPhoneNumbers(item) {
+				scrubbed = true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if scrubPhoneNumbers(item) {
+				scrubbed = true
+			}
+		}
+	}
+
+	return scrubbed
+}
+
+// scrubPhoneNumber replaces a phone number or the phone numbers of a list.
+func scrubPhoneNumber(value any) any {
+	switch v := value.(type) {
+	case string:
+		return ScrubbedPhoneNumber
+	case []any:
+		for i, item := range v {
+			if _, ok := item.(string); ok {
+				v[i] = ScrubbedPhoneNumber
+			}
+		}
+	}
+
+	return value
+}
+
+// scrubHeaders returns a copy of the headers without credentials.
+func scrubHeaders(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	scrubbed := header.Clone()
+	for _, name := range []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"} {
+		scrubbed.Del(name)
+	}
+
+	return scrubbed
+}
@@ -0,0 +1,57 @@
+package resttest_test
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest/resttest"
+)
+
+func TestScrubPhoneNumbers(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "Phone numbers",
+			body: `{"message":"Hello","phoneNumbers":["+1 555-0100","+3721234"]}`,
+			want: `{"message":"Hello","phoneNumbers":["+10000000000","+10000000000"]}`,
+		},
+		{
+			name: "Nested phone number",
+			body: `{"id":"1234567890123","recipients":[{"phoneNumber":"+79990001234","state":"Pending"}]}`,
+			want: `{"id":"1234567890123","recipients":[{"phoneNumber":"+10000000000","state":"Pending"}]}`,
+		},
+		{
+			name: "Numbers kept",
+			body: `{"phoneNumber":"+79990001234","ttl":12345678901234567890,"url":"https://example.com/?a=1&b=2"}`,
+			want: `{"phoneNumber":"+10000000000","ttl":12345678901234567890,"url":"https://example.com/?a=1&b=2"}`,
+		},
+		{
+			name: "Without phone numbers",
+			body: `{"id": "1234567890123",  "createdAt": "1700000000000"}`,
+			want: `{"id": "1234567890123",  "createdAt": "1700000000000"}`,
+		},
+		{
+			name: "Not JSON",
+			body: `call +79990001234`,
+			want: `call +79990001234`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(resttest.ScrubPhoneNumbers([]byte(tt.body))); got != tt.want {
+				t.Errorf("ScrubPhoneNumbers() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrubPhoneNumberPattern(t *testing.T) {
+	body := `{"text":"+79990001234","id":"1234567890123"}`
+	want := `{"text":"+10000000000","id":"+10000000000"}`
+
+	if got := string(resttest.ScrubPhoneNumberPattern([]byte(body))); got != want {
+		t.Errorf("ScrubPhoneNumberPattern() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+	defaultMultiplier     = 2
+	defaultJitter         = 0.2
+)
+
+// RetryPolicy configures how failed requests are retried.
+//
+// Requests are retried on transport errors and on responses with one of the
+// RetryableStatuses. Only idempotent methods are retried unless
+// RetryNonIdempotent is set.
+type RetryPolicy struct {
+	MaxAttempts        int           // Maximum number of attempts, including the first one; values below 2 disable retries
+	InitialBackoff     time.Duration // Delay before the first retry
+	MaxBackoff         time.Duration // Upper bound for the delay between attempts, longer Retry-After delays stop retries
+	Multiplier         float64       // Backoff growth factor, defaults to 2
+	Jitter             float64       // Random fraction (0..1) applied to each delay
+	RetryableStatuses  []int         // Status codes that trigger a retry
+	RetryNonIdempotent bool          // Also retry non-idempotent methods such as POST
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured: up to 3
+// attempts of idempotent requests with jittered exponential backoff on
+// transport errors and 429, 502, 503 and 504 responses.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultMultiplier,
+		Jitter:         defaultJitter,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNonIdempotent: false,
+	}
+}
+
+// NoRetry returns a policy that makes exactly one attempt.
+func NoRetry() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:        1,
+		InitialBackoff:     0,
+		MaxBackoff:         0,
+		Multiplier:         0,
+		Jitter:             0,
+		RetryableStatuses:  nil,
+		RetryNonIdempotent: false,
+	}
+}
+
+// maxAttempts returns the number of attempts allowed for the given method.
+func (p *RetryPolicy) maxAttempts(method string) int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+
+	if !p.RetryNonIdempotent && !IsIdempotent(method) {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// shouldRetry reports whether the request that failed with err can be retried.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if apiErr, ok := AsAPIError(err); ok {
+		if d, ok := retryAfter(apiErr); ok && p.MaxBackoff > 0 && d > p.MaxBackoff {
+			// the server asks to wait longer than the policy allows
+			return false
+		}
+		return slices.Contains(p.RetryableStatuses, apiErr.StatusCode)
+	}
+
+	// transport errors are always returned as *url.Error by http.Client
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// Delay returns the wait time before the given retry (1-based) of a request
+// that failed with err. Retry-After is honored for 429 and 503 responses,
+// up to MaxBackoff.
+func (p *RetryPolicy) Delay(retry int, err error) time.Duration {
+	if apiErr, ok := AsAPIError(err); ok {
+		if d, ok := retryAfter(apiErr); ok {
+			if p.MaxBackoff > 0 {
+				d = min(d, p.MaxBackoff)
+			}
+			return d
+		}
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = defaultMultiplier
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		//nolint:gosec // jitter doesn't need a cryptographically secure generator
+		backoff *= 1 - jitter + 2*jitter*rand.Float64()
+	}
+
+	return time.Duration(backoff)
+}
+
+// retryAfter returns the Retry-After delay of 429 and 503 responses.
+func retryAfter(apiErr *APIError) (time.Duration, bool) {
+	if apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	return parseRetryAfter(apiErr.Header.Get("Retry-After"), time.Now())
+}
+
+// parseRetryAfter parses the Retry-After header value, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// IsIdempotent reports whether requests with the method can be safely repeated.
+func IsIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sleep waits for d or until ctx is done.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func fastRetryPolicy(attempts int) *rest.RetryPolicy {
+	policy := rest.DefaultRetryPolicy()
+	policy.MaxAttempts = attempts
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestClient_Do_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		policy       *rest.RetryPolicy
+		statuses     []int
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{
+			name:         "GET retried until success",
+			method:       http.MethodGet,
+			policy:       fastRetryPolicy(3),
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 3,
+			wantErr:      false,
+		},
+		{
+			name:         "GET gives up after max attempts",
+			method:       http.MethodGet,
+			policy:       fastRetryPolicy(2),
+			statuses:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+			wantErr:      true,
+		},
+		{
+			name:         "Non-retryable status",
+			method:       http.MethodGet,
+			policy:       fastRetryPolicy(3),
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "POST not retried by default",
+			method:       http.MethodPost,
+			policy:       fastRetryPolicy(3),
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:   "POST retried when opted in",
+			method: http.MethodPost,
+			policy: func() *rest.RetryPolicy {
+				policy := fastRetryPolicy(3)
+				policy.RetryNonIdempotent = true
+				return policy
+			}(),
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+			wantErr:      false,
+		},
+		{
+			name:         "Retries disabled",
+			method:       http.MethodGet,
+			policy:       rest.NoRetry(),
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				n := attempts.Add(1)
+				w.WriteHeader(tt.statuses[n-1])
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := rest.NewClient(rest.Config{
+				BaseURL: server.URL,
+				Retry:   tt.policy,
+			})
+
+			err := client.Do(context.Background(), tt.method, "/", nil, map[string]string{}, &map[string]any{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	policy := fastRetryPolicy(2)
+	policy.MaxBackoff = 2 * time.Second
+	client := rest.NewClient(rest.Config{
+		BaseURL: server.URL,
+		Retry:   policy,
+	})
+
+	start := time.Now()
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil); err != nil {
+		t.Fatalf("Client.Do() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Retry-After not honored, retried after %v", elapsed)
+	}
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		attempts.Store(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := client.Do(ctx, http.MethodGet, "/", nil, nil, nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Client.Do() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		if got := attempts.Load(); got != 1 {
+			t.Errorf("attempts = %d, want 1", got)
+		}
+	})
+}
+
+func TestClient_Do_RetryAfterAboveMaxBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{
+		BaseURL: server.URL,
+		Retry:   fastRetryPolicy(3),
+	})
+
+	start := time.Now()
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil); !rest.IsServerError(err) {
+		t.Errorf("Client.Do() error = %v, want server error", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Client.Do() waited %v", elapsed)
+	}
+
+	if got := fastRetryPolicy(3).Delay(1, &rest.APIError{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": []string{"3600"}},
+	}); got != 5*time.Millisecond {
+		t.Errorf("RetryPolicy.Delay() = %v, want %v", got, 5*time.Millisecond)
+	}
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	DefaultMaxResponseSize  = 10 << 20 // 10 MiB
+	DefaultMaxErrorBodySize = 64 << 10 // 64 KiB
+
+	maxDrainSize = 64 << 10
+)
+
+var (
+	ErrResponseTooLarge = errors.New("response too large")
+	ErrNotArray         = errors.New("response is not a JSON array")
+)
+
+// Stream sends the request and calls fn for every element of the JSON array
+// response as it is decoded, without loading the whole response into memory.
+//
+// The response size limit applies to every element instead of the whole
+// response. Decoding stops at the first error returned by fn, which is
+// returned as is. Requests are not retried once fn has been called.
+func Stream[T any](ctx context.Context, c *Client, req Request, fn func(T) error) error {
+	req, opts := c.resolve(req)
+	ctx, cancel := opts.WithTimeout(ctx)
+	defer cancel()
+
+	return c.execute(ctx, req, opts, func(resp *http.Response) error {
+		called := false
+		err := decodeArray(&elementLimitReader{r: resp.Body, limit: c.config.MaxResponseSize, n: 0}, func(item T) error {
+			called = true
+			return fn(item)
+		})
+		if err != nil && called {
+			return &deliveredError{err: err}
+		}
+		return err
+	})
+}
+
+// deliveredError is returned by a response handler that has already passed
+// part of the response to the caller. Such requests are not retried, as that
+// would deliver the same elements again.
+type deliveredError struct {
+	err error
+}
+
+func (e *deliveredError) Error() string {
+	return e.err.Error()
+}
+
+func (e *deliveredError) Unwrap() error {
+	return e.err
+}
+
+func decodeArray[T any](r *elementLimitReader, fn func(T) error) error {
+	dec := json.NewDecoder(r)
+
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if token == nil {
+		// null is an empty array
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("failed to decode response: %w", ErrNotArray)
+	}
+
+	for dec.More() {
+		item := new(T)
+		if err := dec.Decode(item); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		r.reset()
+
+		if err := fn(*item); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// elementLimitReader fails when more than limit bytes are read between resets.
+type elementLimitReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (l *elementLimitReader) Read(p []byte) (int, error) {
+	if l.limit >= 0 && l.n > l.limit {
+		return 0, fmt.Errorf("%w: element of more than %d bytes", ErrResponseTooLarge, l.limit)
+	}
+
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+
+	return n, err //nolint:wrapcheck // io.Reader errors must not be wrapped
+}
+
+func (l *elementLimitReader) reset() {
+	l.n = 0
+}
+
+// limitReader limits r to n bytes, a negative n means no limit.
+func limitReader(r io.Reader, n int64) io.Reader {
+	if n < 0 {
+		return r
+	}
+
+	return io.LimitReader(r, n)
+}
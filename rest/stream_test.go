@@ -0,0 +1,152 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/items":
+			_, _ = w.Write([]byte(`[{"id":1},{"id":2},{"id":3}]`))
+		case "/null":
+			_, _ = w.Write([]byte(`null`))
+		case "/object":
+			_, _ = w.Write([]byte(`{"id":1}`))
+		case "/large":
+			_, _ = w.Write([]byte(`[{"id":1,"name":"` + strings.Repeat("x", 200) + `"}]`))
+		}
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{
+		BaseURL:         server.URL,
+		MaxResponseSize: 100,
+	})
+
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	stop := errors.New("stop")
+
+	tests := []struct {
+		name    string
+		path    string
+		stopAt  int
+		want    []int
+		wantErr error
+	}{
+		{name: "Array", path: "/items", want: []int{1, 2, 3}},
+		{name: "Stop early", path: "/items", stopAt: 2, want: []int{1, 2}, wantErr: stop},
+		{name: "Null", path: "/null", want: nil},
+		{name: "Not an array", path: "/object", want: nil, wantErr: rest.ErrNotArray},
+		{name: "Element too large", path: "/large", want: nil, wantErr: rest.ErrResponseTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			err := rest.Stream(context.Background(), client, rest.Request{Method: http.MethodGet, Path: tt.path},
+				func(i item) error {
+					got = append(got, i.ID)
+					if len(got) == tt.stopAt {
+						return stop
+					}
+					return nil
+				},
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Stream() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Stream() items = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_NoRetryAfterDelivery(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`[{"id":1},{"id":2},{"id":3}]`))
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{BaseURL: server.URL, Retry: rest.DefaultRetryPolicy()})
+
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	// an error that would be retried if returned by the transport
+	retryable := &url.Error{Op: "Get", URL: server.URL, Err: errors.New("downstream failed")}
+
+	var got []int
+	err := rest.Stream(context.Background(), client, rest.Request{Method: http.MethodGet, Path: "/items"},
+		func(i item) error {
+			got = append(got, i.ID)
+			if len(got) == 2 {
+				return retryable
+			}
+			return nil
+		},
+	)
+	if !errors.Is(err, retryable) {
+		t.Errorf("Stream() error = %v, want %v", err, retryable)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Stream() items = %v, want %v", got, want)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestClient_Do_ResponseSizeLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write([]byte(`"` + strings.Repeat("x", 1000) + `"`))
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{
+		BaseURL:          server.URL,
+		MaxResponseSize:  100,
+		MaxErrorBodySize: 10,
+	})
+
+	var resp string
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, &resp); !errors.Is(err, rest.ErrResponseTooLarge) {
+		t.Errorf("Client.Do() error = %v, want %v", err, rest.ErrResponseTooLarge)
+	}
+
+	err := client.Do(context.Background(), http.MethodGet, "/error", nil, nil, &resp)
+	apiErr, ok := rest.AsAPIError(err)
+	if !ok {
+		t.Fatalf("Client.Do() error = %v, want API error", err)
+	}
+	if len(apiErr.Body) != 10 {
+		t.Errorf("APIError.Body length = %d, want 10", len(apiErr.Body))
+	}
+
+	unlimited := rest.NewClient(rest.Config{
+		BaseURL:         server.URL,
+		MaxResponseSize: -1,
+	})
+	if err := unlimited.Do(context.Background(), http.MethodGet, "/", nil, nil, &resp); err != nil {
+		t.Errorf("Client.Do() error = %v", err)
+	}
+}
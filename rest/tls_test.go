@@ -0,0 +1,151 @@
+package rest_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+// newClientCertificate creates a self-signed client certificate.
+func newClientCertificate(t *testing.T) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, cert
+}
+
+func TestClient_Do_TLS(t *testing.T) {
+	clientCert, clientLeaf := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientLeaf)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	hash := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	pin := "sha256/" + base64.StdEncoding.EncodeToString(hash[:])
+	otherPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	tests := []struct {
+		name    string
+		client  *http.Client
+		config  rest.TLSConfig
+		wantErr error
+		// rejected by the server during the handshake
+		wantHandshakeErr bool
+	}{
+		{
+			name:    "Untrusted",
+			config:  rest.TLSConfig{ClientCertificates: []tls.Certificate{clientCert}},
+			wantErr: rest.ErrUntrustedCertificate,
+		},
+		{
+			name:             "Without client certificate",
+			config:           rest.TLSConfig{RootCAs: rootCA},
+			wantHandshakeErr: true,
+		},
+		{
+			name:   "Trusted",
+			config: rest.TLSConfig{RootCAs: rootCA, ClientCertificates: []tls.Certificate{clientCert}},
+		},
+		{
+			name:   "Pinned",
+			config: rest.TLSConfig{RootCAs: rootCA, Pins: []string{otherPin, pin}, ClientCertificates: []tls.Certificate{clientCert}},
+		},
+		{
+			// the transport of the test server trusts its certificate
+			name:   "Transport roots",
+			client: server.Client(),
+			config: rest.TLSConfig{Pins: []string{pin}, ClientCertificates: []tls.Certificate{clientCert}},
+		},
+		{
+			name:    "Pin mismatch",
+			config:  rest.TLSConfig{RootCAs: rootCA, Pins: []string{otherPin}, ClientCertificates: []tls.Certificate{clientCert}},
+			wantErr: rest.ErrPinMismatch,
+		},
+		{
+			name:    "Invalid root CA",
+			config:  rest.TLSConfig{RootCAs: []byte("not a certificate")},
+			wantErr: rest.ErrInvalidTLSConfig,
+		},
+		{
+			name:    "Invalid pin",
+			config:  rest.TLSConfig{Pins: []string{"sha256/short"}},
+			wantErr: rest.ErrInvalidTLSConfig,
+		},
+		{
+			name:    "Unsupported transport",
+			client:  &http.Client{Transport: struct{ http.RoundTripper }{http.DefaultTransport}},
+			config:  rest.TLSConfig{RootCAs: rootCA},
+			wantErr: rest.ErrInvalidTLSConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := rest.NewClient(rest.Config{
+				Client:  tt.client,
+				BaseURL: server.URL,
+				Retry:   rest.NoRetry(),
+				TLS:     tt.config,
+			})
+
+			err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+			if tt.wantHandshakeErr {
+				if err == nil || errors.Is(err, rest.ErrUntrustedCertificate) || errors.Is(err, rest.ErrPinMismatch) {
+					t.Errorf("Client.Do() error = %v, want handshake failure", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Client.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
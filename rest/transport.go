@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout               = 30 * time.Second
+	defaultDialTimeout           = 5 * time.Second
+	defaultKeepAlive             = 30 * time.Second
+	defaultTLSHandshakeTimeout   = 5 * time.Second
+	defaultResponseHeaderTimeout = 15 * time.Second
+	defaultExpectContinueTimeout = time.Second
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultMaxIdleConns          = 100
+	defaultMaxIdleConnsPerHost   = 10
+)
+
+// TransportConfig tunes the HTTP client created when none is provided.
+// Zero values take the defaults of DefaultTransportConfig.
+type TransportConfig struct {
+	Timeout               time.Duration // Overall timeout of a single attempt including reading the body, defaults to 30s, negative means no timeout
+	DialTimeout           time.Duration // Timeout of establishing a connection, defaults to 5s
+	KeepAlive             time.Duration // Interval of TCP keep-alive probes, defaults to 30s
+	TLSHandshakeTimeout   time.Duration // Timeout of the TLS handshake, defaults to 5s
+	ResponseHeaderTimeout time.Duration // Timeout of waiting for response headers after sending the request, defaults to 15s
+	IdleConnTimeout       time.Duration // Time idle connections are kept open, defaults to 90s
+
+	MaxIdleConns        int // Maximum number of idle connections, defaults to 100
+	MaxIdleConnsPerHost int // Maximum number of idle connections per host, defaults to 10
+	MaxConnsPerHost     int // Optional limit of connections per host, unlimited by default
+
+	DisableHTTP2 bool // Use HTTP/1.1 only
+}
+
+// DefaultTransportConfig returns the default transport settings.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		Timeout:               defaultTimeout,
+		DialTimeout:           defaultDialTimeout,
+		KeepAlive:             defaultKeepAlive,
+		TLSHandshakeTimeout:   defaultTLSHandshakeTimeout,
+		ResponseHeaderTimeout: defaultResponseHeaderTimeout,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+
+		MaxIdleConns:        defaultMaxIdleConns,
+		MaxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+		MaxConnsPerHost:     0,
+
+		DisableHTTP2: false,
+	}
+}
+
+// NewHTTPClient creates an HTTP client with a dedicated connection pool.
+func NewHTTPClient(config TransportConfig) *http.Client {
+	defaults := DefaultTransportConfig()
+	timeout := orDefault(config.Timeout, defaults.Timeout)
+	if timeout < 0 {
+		timeout = 0
+	}
+
+	dialer := &net.Dialer{ //nolint:exhaustruct // defaults
+		Timeout:   orDefault(config.DialTimeout, defaults.DialTimeout),
+		KeepAlive: orDefault(config.KeepAlive, defaults.KeepAlive),
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     !config.DisableHTTP2,
+		TLSHandshakeTimeout:   orDefault(config.TLSHandshakeTimeout, defaults.TLSHandshakeTimeout),
+		ResponseHeaderTimeout: orDefault(config.ResponseHeaderTimeout, defaults.ResponseHeaderTimeout),
+		ExpectContinueTimeout: defaultExpectContinueTimeout,
+		IdleConnTimeout:       orDefault(config.IdleConnTimeout, defaults.IdleConnTimeout),
+		MaxIdleConns:          orDefault(config.MaxIdleConns, defaults.MaxIdleConns),
+		MaxIdleConnsPerHost:   orDefault(config.MaxIdleConnsPerHost, defaults.MaxIdleConnsPerHost),
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+	}
+	if config.DisableHTTP2 {
+		// a non-nil empty map disables HTTP/2
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
+
+func orDefault[T time.Duration | int](value, defaultValue T) T {
+	if value == 0 {
+		return defaultValue
+	}
+
+	return value
+}
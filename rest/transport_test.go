@@ -0,0 +1,100 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            rest.TransportConfig
+		wantTimeout       time.Duration
+		wantIdle          int
+		wantHeaderTimeout time.Duration
+		wantHTTP2         bool
+	}{
+		{
+			name:              "Defaults",
+			config:            rest.TransportConfig{},
+			wantTimeout:       30 * time.Second,
+			wantIdle:          10,
+			wantHeaderTimeout: 15 * time.Second,
+			wantHTTP2:         true,
+		},
+		{
+			name: "Custom",
+			config: rest.TransportConfig{
+				Timeout:               time.Minute,
+				MaxIdleConnsPerHost:   50,
+				ResponseHeaderTimeout: time.Second,
+				DisableHTTP2:          true,
+			},
+			wantTimeout:       time.Minute,
+			wantIdle:          50,
+			wantHeaderTimeout: time.Second,
+			wantHTTP2:         false,
+		},
+		{
+			name:              "Without timeout",
+			config:            rest.TransportConfig{Timeout: -1},
+			wantTimeout:       0,
+			wantIdle:          10,
+			wantHeaderTimeout: 15 * time.Second,
+			wantHTTP2:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := rest.NewHTTPClient(tt.config)
+			transport, ok := client.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Transport = %T, want *http.Transport", client.Transport)
+			}
+
+			if client.Timeout != tt.wantTimeout {
+				t.Errorf("Timeout = %v, want %v", client.Timeout, tt.wantTimeout)
+			}
+			if transport.MaxIdleConnsPerHost != tt.wantIdle {
+				t.Errorf("MaxIdleConnsPerHost = %d, want %d", transport.MaxIdleConnsPerHost, tt.wantIdle)
+			}
+			if transport.ResponseHeaderTimeout != tt.wantHeaderTimeout {
+				t.Errorf("ResponseHeaderTimeout = %v, want %v", transport.ResponseHeaderTimeout, tt.wantHeaderTimeout)
+			}
+			if transport.ForceAttemptHTTP2 != tt.wantHTTP2 {
+				t.Errorf("ForceAttemptHTTP2 = %v, want %v", transport.ForceAttemptHTTP2, tt.wantHTTP2)
+			}
+		})
+	}
+}
+
+func TestClient_Do_TransportTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := rest.NewClient(rest.Config{
+		BaseURL:   server.URL,
+		Retry:     rest.NoRetry(),
+		Transport: rest.TransportConfig{ResponseHeaderTimeout: 20 * time.Millisecond},
+	})
+
+	start := time.Now()
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil, nil); err == nil {
+		t.Fatal("Client.Do() error = nil, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Client.Do() took %v, want timeout after 20ms", elapsed)
+	}
+}
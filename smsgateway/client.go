@@ -2,10 +2,12 @@ package smsgateway
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"sync/atomic"
+	"time"
 
 	"github.com/android-sms-gateway/client-go/rest"
 )
@@ -14,49 +16,123 @@ import (
 const BASE_URL = "https://api.sms-gate.app/3rdparty/v1"
 
 type Config struct {
-	Client   *http.Client // Optional HTTP Client, defaults to `http.DefaultClient`
-	BaseURL  string       // Optional base URL, defaults to `https://api.sms-gate.app/3rdparty/v1`
-	User     string       // Required username
-	Password string       // Required password
+	Client   *http.Client      // Optional HTTP Client, defaults to a client created with `Transport`
+	BaseURL  string            // Optional base URL, defaults to `https://api.sms-gate.app/3rdparty/v1`
+	User     string            // Username, required unless `Credentials` or `Authenticator` is set
+	Password string            // Password, required unless `Credentials` or `Authenticator` is set
+	Retry    *rest.RetryPolicy // Optional retry policy, defaults to `rest.DefaultRetryPolicy()`
+	Limits   *RateLimits       // Optional client-side rate limits, no limits by default
+
+	TLS       rest.TLSConfig       // Optional root CAs, certificate pins and client certificates
+	Transport rest.TransportConfig // Optional tuning of the default HTTP client, ignored if `Client` is set
+
+	// Optional source of rotating credentials, consulted for every request. Overrides `User` and `Password`.
+	Credentials CredentialsProvider
+	// Optional authenticator invoked for every request. Overrides any credentials.
+	Authenticator rest.Authenticator
+
+	CircuitBreaker *rest.CircuitBreaker // Optional circuit breaker for the base URL, disabled by default
+	Cache          *rest.Cache          // Optional cache of read endpoints such as `ListDevices`, disabled by default
+
+	// Optional ordered list of servers to fail over between. If set, `BaseURL`, `User`,
+	// `Password`, `Credentials`, `Authenticator` and `CircuitBreaker` are ignored.
+	Endpoints []Endpoint
+	// Optional interval of health checks of failed endpoints, defaults to 30 seconds.
+	HealthCheckInterval time.Duration
+
+	Middlewares []rest.Middleware // Optional middlewares around every HTTP request
+
+	Logger    *slog.Logger // Optional logger, logging is disabled by default
+	LogBodies bool         // Log request and response bodies at debug level
+	Redact    Redaction    // Masking of personal data in logs and API errors
+
+	MaxResponseSize  int64            // Optional limit of response body size, defaults to 10 MiB, negative means no limit
+	CoalesceRequests bool             // Share a single in-flight request between concurrent identical reads such as `GetState`
+	Compression      rest.Compression // Optional compression of request bodies
 }
 
 type Client struct {
-	*rest.Client
+	*rest.Client // Client of the first endpoint
 
-	headers map[string]string
+	endpoints *endpointPool
+	limits    *RateLimits
+	retry     *rest.RetryPolicy
 }
 
 // Sends an SMS message.
-func (c *Client) Send(ctx context.Context, message Message) (MessageState, error) {
-	path := "/message"
-	resp := new(MessageState)
+func (c *Client) Send(ctx context.Context, message Message, opts ...rest.CallOption) (MessageState, error) {
+	ctx = rest.WithOperation(ctx, "Send")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	if err := c.limits.wait(ctx, RouteSend, message.Priority); err != nil {
+		return MessageState{}, fmt.Errorf("failed to send message: %w", err)
+	}
 
-	if err := c.Do(ctx, http.MethodPost, path, c.headers, &message, resp); err != nil {
-		return *resp, fmt.Errorf("failed to send message: %w", err)
+	resp, err := failover[MessageState](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodPost,
+		Path:    "/message",
+		Query:   nil,
+		Headers: nil,
+		Payload: &message,
+		Options: opts,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to send message: %w", err)
 	}
 
-	return *resp, nil
+	return resp, nil
 }
 
 // Gets the state of an SMS message by ID.
-func (c *Client) GetState(ctx context.Context, messageID string) (MessageState, error) {
-	path := fmt.Sprintf("/message/%s", messageID)
-	resp := new(MessageState)
+//
+// The call is not failed over, as other endpoints don't know the message.
+func (c *Client) GetState(ctx context.Context, messageID string, opts ...rest.CallOption) (MessageState, error) {
+	ctx = rest.WithOperation(ctx, "GetState")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	opts = c.endpoints.pin(opts)
 
-	if err := c.Do(ctx, http.MethodGet, path, c.headers, nil, resp); err != nil {
-		return *resp, fmt.Errorf("failed to get message state: %w", err)
+	if err := c.limits.wait(ctx, RouteGetState, PriorityDefault); err != nil {
+		return MessageState{}, fmt.Errorf("failed to get message state: %w", err)
 	}
 
-	return *resp, nil
+	resp, err := failover[MessageState](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodGet,
+		Path:    fmt.Sprintf("/message/%s", messageID),
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get message state: %w", err)
+	}
+
+	return resp, nil
 }
 
 // ListWebhooks retrieves all registered webhooks.
 // Returns a slice of Webhook objects or an error if the request fails.
-func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
-	path := "/webhooks"
-	resp := []Webhook{}
+func (c *Client) ListWebhooks(ctx context.Context, opts ...rest.CallOption) ([]Webhook, error) {
+	ctx = rest.WithOperation(ctx, "ListWebhooks")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
 
-	if err := c.Do(ctx, http.MethodGet, path, c.headers, nil, &resp); err != nil {
+	if err := c.limits.wait(ctx, RouteListWebhooks, PriorityDefault); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	resp, err := failover[[]Webhook](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/webhooks",
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	})
+	if err != nil {
 		return resp, fmt.Errorf("failed to list webhooks: %w", err)
 	}
 
@@ -65,23 +141,54 @@ func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
 
 // RegisterWebhook registers a new webhook.
 // Returns the registered webhook with server-assigned fields or an error if the request fails.
-func (c *Client) RegisterWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
-	path := "/webhooks"
-	resp := new(Webhook)
+func (c *Client) RegisterWebhook(ctx context.Context, webhook Webhook, opts ...rest.CallOption) (Webhook, error) {
+	ctx = rest.WithOperation(ctx, "RegisterWebhook")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	if err := c.limits.wait(ctx, RouteRegisterWebhook, PriorityDefault); err != nil {
+		return Webhook{}, fmt.Errorf("failed to register webhook: %w", err)
+	}
 
-	if err := c.Do(ctx, http.MethodPost, path, c.headers, &webhook, resp); err != nil {
-		return *resp, fmt.Errorf("failed to register webhook: %w", err)
+	resp, err := failover[Webhook](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodPost,
+		Path:    "/webhooks",
+		Query:   nil,
+		Headers: nil,
+		Payload: &webhook,
+		Options: opts,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to register webhook: %w", err)
 	}
 
-	return *resp, nil
+	return resp, nil
 }
 
 // DeleteWebhook removes a webhook with the specified ID.
 // Returns an error if the deletion fails.
-func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
-	path := fmt.Sprintf("/webhooks/%s", url.PathEscape(webhookID))
+//
+// The call is not failed over, as other endpoints don't know the webhook.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string, opts ...rest.CallOption) error {
+	ctx = rest.WithOperation(ctx, "DeleteWebhook")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	opts = c.endpoints.pin(opts)
+
+	if err := c.limits.wait(ctx, RouteDeleteWebhook, PriorityDefault); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
 
-	if err := c.Do(ctx, http.MethodDelete, path, c.headers, nil, nil); err != nil {
+	_, err := failover[struct{}](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodDelete,
+		Path:    "/webhooks/" + url.PathEscape(webhookID),
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
 
@@ -90,30 +197,198 @@ func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
 
 // ListDevices retrieves all registered devices in the account.
 // Returns a slice of Device objects or an error if the request fails.
-func (c *Client) ListDevices(ctx context.Context) ([]Device, error) {
-	path := "/device"
-	resp := []Device{}
+func (c *Client) ListDevices(ctx context.Context, opts ...rest.CallOption) ([]Device, error) {
+	ctx = rest.WithOperation(ctx, "ListDevices")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
 
-	if err := c.Do(ctx, http.MethodGet, path, c.headers, nil, &resp); err != nil {
+	if err := c.limits.wait(ctx, RouteListDevices, PriorityDefault); err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	resp, err := failover[[]Device](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/device",
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	})
+	if err != nil {
 		return resp, fmt.Errorf("failed to list devices: %w", err)
 	}
 
 	return resp, nil
 }
 
+// GetLogs retrieves device log entries created between from and to.
+// A zero from or to leaves the range open on that side.
+// Use LogsFilter to select entries by priority, module or context.
+func (c *Client) GetLogs(ctx context.Context, from, to time.Time, opts ...rest.CallOption) ([]LogEntry, error) {
+	ctx = rest.WithOperation(ctx, "GetLogs")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	if err := c.limits.wait(ctx, RouteGetLogs, PriorityDefault); err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
+	}
+
+	query := url.Values{}
+	if !from.IsZero() {
+		query.Set("from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.RFC3339))
+	}
+
+	resp, err := failover[GetLogsResponse](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/logs",
+		Query:   query,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get logs: %w", err)
+	}
+
+	return resp, nil
+}
+
+// StreamWebhooks calls fn for every registered webhook as it is received,
+// without loading the whole list into memory.
+// Stops at the first error returned by fn.
+func (c *Client) StreamWebhooks(ctx context.Context, fn func(Webhook) error, opts ...rest.CallOption) error {
+	ctx = rest.WithOperation(ctx, "StreamWebhooks")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	if err := c.limits.wait(ctx, RouteListWebhooks, PriorityDefault); err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	err := failoverStream(ctx, c.endpoints, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/webhooks",
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	}, fn)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return nil
+}
+
+// StreamDevices calls fn for every registered device as it is received,
+// without loading the whole list into memory.
+// Stops at the first error returned by fn.
+func (c *Client) StreamDevices(ctx context.Context, fn func(Device) error, opts ...rest.CallOption) error {
+	ctx = rest.WithOperation(ctx, "StreamDevices")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	if err := c.limits.wait(ctx, RouteListDevices, PriorityDefault); err != nil {
+		return fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	err := failoverStream(ctx, c.endpoints, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/device",
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	}, fn)
+	if err != nil {
+		return fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	return nil
+}
+
+// CheckHealth retrieves the health status of the server.
+func (c *Client) CheckHealth(ctx context.Context, opts ...rest.CallOption) (HealthResponse, error) {
+	ctx = rest.WithOperation(ctx, "CheckHealth")
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	resp, err := failover[HealthResponse](ctx, c.endpoints, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/health",
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: opts,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to check health: %w", err)
+	}
+
+	return resp, nil
+}
+
+// withCallTimeout limits ctx by the timeout of the call, shared by all
+// endpoints, attempts and rate limit waits.
+func withCallTimeout(ctx context.Context, opts []rest.CallOption) (context.Context, context.CancelFunc) {
+	return rest.NewCallOptions(opts...).WithTimeout(ctx)
+}
+
+// Close stops background health checks of failed endpoints.
+func (c *Client) Close() error {
+	c.endpoints.close()
+
+	return nil
+}
+
 // NewClient creates a new instance of the API Client.
 func NewClient(config Config) *Client {
 	if config.BaseURL == "" {
 		config.BaseURL = BASE_URL
 	}
+	if config.Retry == nil {
+		config.Retry = rest.DefaultRetryPolicy()
+	}
+	if config.Client == nil {
+		// shared by all endpoints, each one gets a copy of the transport if TLS is set
+		config.Client = rest.NewHTTPClient(config.Transport)
+	}
+
+	endpoints := config.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []Endpoint{
+			{
+				BaseURL:        config.BaseURL,
+				User:           config.User,
+				Password:       config.Password,
+				Credentials:    config.Credentials,
+				Authenticator:  config.Authenticator,
+				CircuitBreaker: config.CircuitBreaker,
+			},
+		}
+	}
+
+	pool := make([]*endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		restConfig := config.rest()
+		restConfig.BaseURL = e.BaseURL
+		restConfig.Authenticator = e.authenticator()
+		restConfig.CircuitBreaker = e.CircuitBreaker
+
+		pool = append(pool, &endpoint{
+			baseURL: e.BaseURL,
+			client:  rest.NewClient(restConfig),
+			down:    atomic.Bool{},
+		})
+	}
 
 	return &Client{
-		Client: rest.NewClient(rest.Config{
-			Client:  config.Client,
-			BaseURL: config.BaseURL,
-		}),
-		headers: map[string]string{
-			"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(config.User+":"+config.Password)),
-		},
+		Client:    pool[0].client,
+		endpoints: newEndpointPool(pool, config.Retry, config.HealthCheckInterval),
+		limits:    config.Limits,
+		retry:     config.Retry,
 	}
 }
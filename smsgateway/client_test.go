@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/android-sms-gateway/client-go/rest"
 	"github.com/android-sms-gateway/client-go/smsgateway"
 )
 
@@ -358,3 +361,224 @@ func TestClient_DeleteWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/logs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.URL.Query().Get("from") == "2024-01-01T00:00:00Z" && r.URL.Query().Get("to") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":1,"priority":"ERROR","module":"messages","message":"` +
+			r.URL.RawQuery + `","context":{"messageId":"123"},"createdAt":"2024-01-01T12:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+	})
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 3, 0, 0, 0, time.FixedZone("", 3*60*60))
+
+	tests := []struct {
+		name    string
+		from    time.Time
+		to      time.Time
+		want    []smsgateway.LogEntry
+		wantErr bool
+	}{
+		{
+			name: "Range",
+			from: from,
+			to:   to,
+			want: []smsgateway.LogEntry{
+				{
+					ID:        1,
+					Priority:  smsgateway.LogEntryPriorityError,
+					Module:    "messages",
+					Message:   "from=2024-01-01T00%3A00%3A00Z&to=2024-01-02T03%3A00%3A00%2B03%3A00",
+					Context:   map[string]string{"messageId": "123"},
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "Open range",
+			want: []smsgateway.LogEntry{
+				{
+					ID:        1,
+					Priority:  smsgateway.LogEntryPriorityError,
+					Module:    "messages",
+					Message:   "",
+					Context:   map[string]string{"messageId": "123"},
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:    "Error",
+			from:    from,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.GetLogs(context.Background(), tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.GetLogs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.GetLogs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_StreamDevices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/device" || r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"1","name":"Phone 1"},{"id":"2","name":"Phone 2"}]`))
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+	})
+
+	var names []string
+	err := client.StreamDevices(context.Background(), func(d smsgateway.Device) error {
+		names = append(names, d.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Client.StreamDevices() error = %v", err)
+	}
+	if want := []string{"Phone 1", "Phone 2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Client.StreamDevices() = %v, want %v", names, want)
+	}
+}
+
+func TestClient_Authenticator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":"123","state":"Pending"}`))
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL:       server.URL,
+		User:          "user",
+		Password:      "pass",
+		Authenticator: rest.BearerToken("token"),
+	})
+
+	if _, err := client.GetState(context.Background(), "123"); err != nil {
+		t.Fatalf("Client.GetState() error = %v", err)
+	}
+}
+
+func BenchmarkClient_Send(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"id":"123","state":"Pending","recipients":[{"phoneNumber":"+79990001234","state":"Pending"}]}`))
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+	})
+	message := smsgateway.Message{
+		Message:      "Hello, world!",
+		PhoneNumbers: []string{"+79990001234"},
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := client.Send(ctx, message); err != nil {
+			b.Fatalf("Send() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkClient_GetState(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"123","state":"Delivered","recipients":[{"phoneNumber":"+79990001234","state":"Delivered"}]}`))
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+	})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := client.GetState(ctx, "123"); err != nil {
+			b.Fatalf("GetState() error = %v", err)
+		}
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/health":
+			_, _ = w.Write([]byte(`{"status":"pass"}`))
+		case "/device":
+			_, _ = w.Write([]byte(`[{"id":"1"}]`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"123","state":"Pending"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+		Cache:   rest.NewCache(rest.CacheConfig{TTL: time.Hour}),
+	})
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := client.ListDevices(ctx); err != nil {
+			t.Fatalf("ListDevices() error = %v", err)
+		}
+		if _, err := client.GetState(ctx, "123"); err != nil {
+			t.Fatalf("GetState() error = %v", err)
+		}
+		if _, err := client.CheckHealth(ctx); err != nil {
+			t.Fatalf("CheckHealth() error = %v", err)
+		}
+	}
+
+	want := map[string]int{"/device": 1, "/message/123": 2, "/health": 2}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
@@ -0,0 +1,192 @@
+package smsgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+const (
+	EnvUsername = "ASG_USERNAME"
+	EnvPassword = "ASG_PASSWORD"
+
+	defaultCredentialsCheckInterval = 10 * time.Second
+)
+
+var ErrNoCredentials = errors.New("no credentials")
+
+// Credentials is a username and password pair.
+type Credentials struct {
+	User     string
+	Password string
+}
+
+// CredentialsProvider supplies the current credentials. It is called for
+// every request and must be safe for concurrent use.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc adapts a function to the CredentialsProvider interface.
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+func (f CredentialsFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// EnvCredentials returns a provider that reads the credentials from the
+// `ASG_USERNAME` and `ASG_PASSWORD` environment variables.
+func EnvCredentials() CredentialsProvider {
+	return CredentialsFunc(func(_ context.Context) (Credentials, error) {
+		creds := Credentials{
+			User:     os.Getenv(EnvUsername),
+			Password: os.Getenv(EnvPassword),
+		}
+		if creds.User == "" || creds.Password == "" {
+			return Credentials{}, fmt.Errorf("%w: %s and %s must be set", ErrNoCredentials, EnvUsername, EnvPassword)
+		}
+
+		return creds, nil
+	})
+}
+
+// FileCredentials reads the username and password from separate files, such
+// as secrets rendered by a Vault agent. The files are checked for changes at
+// most once per interval and reloaded when their modification time or size
+// changes. Surrounding whitespace is trimmed.
+type FileCredentials struct {
+	userFile     string
+	passwordFile string
+	interval     time.Duration
+
+	current   atomic.Pointer[Credentials]
+	checkedAt atomic.Int64 // unix nanoseconds of the last check
+
+	mu      sync.Mutex // serializes reloads
+	version string     // modification times and sizes of the loaded files
+}
+
+// NewFileCredentials creates a FileCredentials and loads the files.
+// The interval defaults to 10 seconds.
+func NewFileCredentials(userFile, passwordFile string, interval time.Duration) (*FileCredentials, error) {
+	if interval <= 0 {
+		interval = defaultCredentialsCheckInterval
+	}
+
+	f := &FileCredentials{
+		userFile:     userFile,
+		passwordFile: passwordFile,
+		interval:     interval,
+
+		current:   atomic.Pointer[Credentials]{},
+		checkedAt: atomic.Int64{},
+
+		mu:      sync.Mutex{},
+		version: "",
+	}
+	if err := f.reload(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Credentials returns the last loaded credentials, reloading them first if
+// the files have changed. If a reload fails, the previous credentials are kept.
+func (f *FileCredentials) Credentials(_ context.Context) (Credentials, error) {
+	if time.Since(time.Unix(0, f.checkedAt.Load())) >= f.interval {
+		_ = f.reload()
+	}
+
+	return *f.current.Load(), nil
+}
+
+// reload loads the files if they have changed since the last load.
+func (f *FileCredentials) reload() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.current.Load() != nil && time.Since(time.Unix(0, f.checkedAt.Load())) < f.interval {
+		// reloaded by another goroutine
+		return nil
+	}
+	f.checkedAt.Store(time.Now().UnixNano())
+
+	version, err := fileVersion(f.userFile, f.passwordFile)
+	if err != nil {
+		return err
+	}
+	if version == f.version {
+		return nil
+	}
+
+	user, err := os.ReadFile(f.userFile)
+	if err != nil {
+		return fmt.Errorf("failed to read username: %w", err)
+	}
+	password, err := os.ReadFile(f.passwordFile)
+	if err != nil {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+
+	f.current.Store(&Credentials{
+		User:     strings.TrimSpace(string(user)),
+		Password: strings.TrimSpace(string(password)),
+	})
+	f.version = version
+
+	return nil
+}
+
+// fileVersion identifies the current contents of the files by their
+// modification times and sizes.
+func fileVersion(names ...string) (string, error) {
+	var b strings.Builder
+	for _, name := range names {
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat credentials file: %w", err)
+		}
+		fmt.Fprintf(&b, "%d:%d;", info.ModTime().UnixNano(), info.Size())
+	}
+
+	return b.String(), nil
+}
+
+// credentialsAuth authenticates requests with Basic auth using the current
+// credentials of the provider.
+type credentialsAuth struct {
+	provider CredentialsProvider
+}
+
+func (a credentialsAuth) Authenticate(req *http.Request) error {
+	creds, err := a.provider.Credentials(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	req.SetBasicAuth(creds.User, creds.Password)
+
+	return nil
+}
+
+// authenticator returns the authenticator of the endpoint, preferring an
+// explicit authenticator over the credentials provider and static credentials.
+func (e Endpoint) authenticator() rest.Authenticator {
+	if e.Authenticator != nil {
+		return e.Authenticator
+	}
+	if e.Credentials != nil {
+		return credentialsAuth{provider: e.Credentials}
+	}
+
+	return rest.BasicAuth(e.User, e.Password)
+}
@@ -0,0 +1,150 @@
+package smsgateway_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		want     smsgateway.Credentials
+		wantErr  error
+	}{
+		{
+			name:     "Set",
+			user:     "user",
+			password: "pass",
+			want:     smsgateway.Credentials{User: "user", Password: "pass"},
+		},
+		{
+			name:    "Missing",
+			user:    "user",
+			wantErr: smsgateway.ErrNoCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(smsgateway.EnvUsername, tt.user)
+			t.Setenv(smsgateway.EnvPassword, tt.password)
+
+			got, err := smsgateway.EnvCredentials().Credentials(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Credentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Credentials() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	userFile := filepath.Join(dir, "username")
+	passwordFile := filepath.Join(dir, "password")
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(userFile, "user\n")
+	write(passwordFile, "old\n")
+
+	provider, err := smsgateway.NewFileCredentials(userFile, passwordFile, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFileCredentials() error = %v", err)
+	}
+
+	got, _ := provider.Credentials(context.Background())
+	if want := (smsgateway.Credentials{User: "user", Password: "old"}); got != want {
+		t.Errorf("Credentials() = %+v, want %+v", got, want)
+	}
+
+	write(passwordFile, "rotated\n")
+	time.Sleep(5 * time.Millisecond)
+
+	got, _ = provider.Credentials(context.Background())
+	if want := (smsgateway.Credentials{User: "user", Password: "rotated"}); got != want {
+		t.Errorf("Credentials() after rotation = %+v, want %+v", got, want)
+	}
+
+	// a failed reload keeps the previous credentials
+	if err := os.Remove(passwordFile); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	got, _ = provider.Credentials(context.Background())
+	if want := (smsgateway.Credentials{User: "user", Password: "rotated"}); got != want {
+		t.Errorf("Credentials() after removal = %+v, want %+v", got, want)
+	}
+
+	if _, err := smsgateway.NewFileCredentials(userFile, passwordFile, 0); err == nil {
+		t.Error("NewFileCredentials() with missing file error = nil")
+	}
+}
+
+func TestClient_CredentialsRotation(t *testing.T) {
+	var mu sync.Mutex
+	password := "old"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		want := password
+		mu.Unlock()
+
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || (pass != want && pass != "old") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"123","state":"Pending"}`))
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+		Credentials: smsgateway.CredentialsFunc(func(context.Context) (smsgateway.Credentials, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return smsgateway.Credentials{User: "user", Password: password}, nil
+		}),
+	})
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		if i == 10 {
+			mu.Lock()
+			password = "new"
+			mu.Unlock()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Send(context.Background(), smsgateway.Message{
+				Message:      "Hello",
+				PhoneNumbers: []string{"+79990001234"},
+			}); err != nil {
+				t.Errorf("Client.Send() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,6 +1,9 @@
 package smsgateway
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type LogEntryPriority string
 
@@ -26,3 +29,55 @@ type LogEntry struct {
 	// The timestamp when this log entry was created.
 	CreatedAt time.Time `json:"createdAt"`
 }
+
+// LogsFilter selects log entries on the client side. Zero fields match all entries.
+type LogsFilter struct {
+	// Minimum priority of the entries, entries with an unknown priority don't match.
+	MinPriority LogEntryPriority
+	// Modules the entries were generated by.
+	Modules []string
+	// Context keys the entries must contain, with the same value unless it is empty.
+	Context map[string]string
+}
+
+// Match reports whether the entry is selected by the filter.
+func (f LogsFilter) Match(entry LogEntry) bool {
+	if f.MinPriority != "" && entry.Priority.rank() < f.MinPriority.rank() {
+		return false
+	}
+
+	if len(f.Modules) > 0 && !slices.Contains(f.Modules, entry.Module) {
+		return false
+	}
+
+	for key, value := range f.Context {
+		got, ok := entry.Context[key]
+		if !ok || (value != "" && got != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns the entries selected by the filter.
+func (f LogsFilter) Filter(entries []LogEntry) []LogEntry {
+	selected := make([]LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if f.Match(entry) {
+			selected = append(selected, entry)
+		}
+	}
+
+	return selected
+}
+
+// rank orders priorities from debug to error, -1 for unknown priorities.
+func (p LogEntryPriority) rank() int {
+	return slices.Index([]LogEntryPriority{
+		LogEntryPriorityDebug,
+		LogEntryPriorityInfo,
+		LogEntryPriorityWarn,
+		LogEntryPriorityError,
+	}, p)
+}
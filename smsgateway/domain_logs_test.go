@@ -0,0 +1,98 @@
+package smsgateway_test
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestLogsFilter_Match(t *testing.T) {
+	entry := smsgateway.LogEntry{
+		ID:       1,
+		Priority: smsgateway.LogEntryPriorityWarn,
+		Module:   "messages",
+		Message:  "Message failed",
+		Context:  map[string]string{"messageId": "123", "reason": "timeout"},
+	}
+
+	tests := []struct {
+		name   string
+		filter smsgateway.LogsFilter
+		entry  smsgateway.LogEntry
+		want   bool
+	}{
+		{
+			name:   "Empty filter",
+			filter: smsgateway.LogsFilter{},
+			entry:  entry,
+			want:   true,
+		},
+		{
+			name:   "Lower min priority",
+			filter: smsgateway.LogsFilter{MinPriority: smsgateway.LogEntryPriorityInfo},
+			entry:  entry,
+			want:   true,
+		},
+		{
+			name:   "Same min priority",
+			filter: smsgateway.LogsFilter{MinPriority: smsgateway.LogEntryPriorityWarn},
+			entry:  entry,
+			want:   true,
+		},
+		{
+			name:   "Higher min priority",
+			filter: smsgateway.LogsFilter{MinPriority: smsgateway.LogEntryPriorityError},
+			entry:  entry,
+			want:   false,
+		},
+		{
+			name:   "Unknown priority",
+			filter: smsgateway.LogsFilter{MinPriority: smsgateway.LogEntryPriorityDebug},
+			entry:  smsgateway.LogEntry{Priority: "TRACE"},
+			want:   false,
+		},
+		{
+			name:   "Module",
+			filter: smsgateway.LogsFilter{Modules: []string{"webhooks", "messages"}},
+			entry:  entry,
+			want:   true,
+		},
+		{
+			name:   "Other module",
+			filter: smsgateway.LogsFilter{Modules: []string{"webhooks"}},
+			entry:  entry,
+			want:   false,
+		},
+		{
+			name:   "Context key",
+			filter: smsgateway.LogsFilter{Context: map[string]string{"messageId": ""}},
+			entry:  entry,
+			want:   true,
+		},
+		{
+			name:   "Context value",
+			filter: smsgateway.LogsFilter{Context: map[string]string{"messageId": "123", "reason": "timeout"}},
+			entry:  entry,
+			want:   true,
+		},
+		{
+			name:   "Other context value",
+			filter: smsgateway.LogsFilter{Context: map[string]string{"messageId": "456"}},
+			entry:  entry,
+			want:   false,
+		},
+		{
+			name:   "Missing context key",
+			filter: smsgateway.LogsFilter{Context: map[string]string{"deviceId": ""}},
+			entry:  entry,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.entry); got != tt.want {
+				t.Errorf("LogsFilter.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
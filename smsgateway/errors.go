@@ -0,0 +1,55 @@
+package smsgateway
+
+import (
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+// AsErrorResponse extracts the decoded error response from an API error.
+// Returns false if err is not an API error.
+func AsErrorResponse(err error) (ErrorResponse, bool) {
+	apiErr, ok := rest.AsAPIError(err)
+	if !ok {
+		return ErrorResponse{}, false
+	}
+
+	return ErrorResponse{
+		Message: apiErr.Message,
+		Code:    apiErr.Code,
+		Data:    apiErr.Data,
+	}, true
+}
+
+// IsBadRequest reports whether err is caused by a 400 Bad Request response.
+func IsBadRequest(err error) bool {
+	return rest.IsBadRequest(err)
+}
+
+// IsUnauthorized reports whether err is caused by a 401 Unauthorized response.
+func IsUnauthorized(err error) bool {
+	return rest.IsUnauthorized(err)
+}
+
+// IsForbidden reports whether err is caused by a 403 Forbidden response.
+func IsForbidden(err error) bool {
+	return rest.IsForbidden(err)
+}
+
+// IsNotFound reports whether err is caused by a 404 Not Found response.
+func IsNotFound(err error) bool {
+	return rest.IsNotFound(err)
+}
+
+// IsConflict reports whether err is caused by a 409 Conflict response.
+func IsConflict(err error) bool {
+	return rest.IsConflict(err)
+}
+
+// IsRateLimited reports whether err is caused by a 429 Too Many Requests response.
+func IsRateLimited(err error) bool {
+	return rest.IsRateLimited(err)
+}
+
+// IsServerError reports whether err is caused by a 5xx response.
+func IsServerError(err error) bool {
+	return rest.IsServerError(err)
+}
@@ -0,0 +1,42 @@
+package smsgateway_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestAsErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"message not found","code":404}`))
+	}))
+	defer server.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+	})
+
+	_, err := client.GetState(context.Background(), "123")
+	if !smsgateway.IsNotFound(err) {
+		t.Fatalf("IsNotFound() = false for %v", err)
+	}
+	if smsgateway.IsUnauthorized(err) {
+		t.Errorf("IsUnauthorized() = true for %v", err)
+	}
+
+	resp, ok := smsgateway.AsErrorResponse(err)
+	if !ok {
+		t.Fatalf("AsErrorResponse() = false for %v", err)
+	}
+	if resp.Message != "message not found" || resp.Code != 404 {
+		t.Errorf("AsErrorResponse() = %+v", resp)
+	}
+
+	if _, ok := smsgateway.AsErrorResponse(context.Canceled); ok {
+		t.Errorf("AsErrorResponse() = true for non-API error")
+	}
+}
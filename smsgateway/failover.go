@@ -0,0 +1,272 @@
+package smsgateway
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+const defaultHealthCheckInterval = 30 * time.Second
+
+// Endpoint is a gateway server with its own credentials.
+type Endpoint struct {
+	BaseURL  string // Required base URL
+	User     string // Username, required unless `Credentials` or `Authenticator` is set
+	Password string // Password, required unless `Credentials` or `Authenticator` is set
+
+	Credentials   CredentialsProvider // Optional source of rotating credentials, overrides `User` and `Password`
+	Authenticator rest.Authenticator  // Optional authenticator, overrides any credentials
+
+	CircuitBreaker *rest.CircuitBreaker // Optional circuit breaker, disabled by default
+}
+
+type endpoint struct {
+	baseURL string
+	client  *rest.Client
+
+	down atomic.Bool
+}
+
+// endpointPool sends requests to the first available endpoint and fails over
+// to the next one on connection errors and 5xx responses. Non-idempotent
+// requests such as Send only fail over if they cannot have reached the server,
+// unless the retry policy allows retrying them. Calls on resources of a single
+// server, such as GetState, are pinned to one endpoint instead.
+//
+// Failed endpoints are moved to the end of the rotation and health-checked in
+// the background until they recover.
+type endpointPool struct {
+	endpoints []*endpoint
+	retry     *rest.RetryPolicy // default policy deciding on failover of non-idempotent requests
+	interval  time.Duration
+
+	ctx    context.Context // cancels background health checks
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newEndpointPool(endpoints []*endpoint, retry *rest.RetryPolicy, interval time.Duration) *endpointPool {
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &endpointPool{
+		endpoints: endpoints,
+		retry:     retry,
+		interval:  interval,
+
+		ctx:    ctx,
+		cancel: cancel,
+		wg:     sync.WaitGroup{},
+	}
+}
+
+// failover sends the request to the available endpoints in order until one of them
+// succeeds or fails with an error that is not worth failing over.
+func failover[T any](ctx context.Context, p *endpointPool, req rest.Request) (T, error) {
+	ctx = withRequestID(ctx)
+
+	var (
+		resp T
+		err  error
+	)
+	candidates, pinned := p.candidates(req)
+	for _, e := range candidates {
+		resp, err = rest.Do[T](ctx, e.client, req)
+		if err == nil {
+			e.down.Store(false)
+			return resp, nil
+		}
+
+		if pinned || len(p.endpoints) == 1 || !p.isFailoverError(ctx, req, err) {
+			return resp, err
+		}
+
+		p.markDown(e)
+	}
+
+	return resp, err
+}
+
+// failoverStream is failover for streamed responses. It doesn't fail over
+// once fn has been called.
+func failoverStream[T any](ctx context.Context, p *endpointPool, req rest.Request, fn func(T) error) error {
+	ctx = withRequestID(ctx)
+
+	var err error
+	candidates, pinned := p.candidates(req)
+	for _, e := range candidates {
+		called := false
+		err = rest.Stream(ctx, e.client, req, func(item T) error {
+			called = true
+			return fn(item)
+		})
+		if err == nil {
+			e.down.Store(false)
+			return nil
+		}
+
+		if called || pinned || len(p.endpoints) == 1 || !p.isFailoverError(ctx, req, err) {
+			return err
+		}
+
+		p.markDown(e)
+	}
+
+	return err
+}
+
+// withRequestID makes attempts on all endpoints share the request ID.
+func withRequestID(ctx context.Context) context.Context {
+	if rest.RequestIDFromContext(ctx) != "" {
+		return ctx
+	}
+
+	return rest.WithRequestID(ctx, rest.NewRequestID())
+}
+
+// candidates returns healthy endpoints followed by failed ones as a last resort.
+//
+// If the request overrides the base URL, the call is pinned to the endpoint with
+// that URL, or to the first endpoint whose credentials are used for unknown URLs.
+func (p *endpointPool) candidates(req rest.Request) ([]*endpoint, bool) {
+	if baseURL := rest.NewCallOptions(req.Options...).BaseURL; baseURL != "" {
+		for _, e := range p.endpoints {
+			if e.baseURL == baseURL {
+				return []*endpoint{e}, true
+			}
+		}
+
+		return p.endpoints[:1], true
+	}
+
+	healthy := make([]*endpoint, 0, len(p.endpoints))
+	failed := []*endpoint{}
+	for _, e := range p.endpoints {
+		if e.down.Load() {
+			failed = append(failed, e)
+		} else {
+			healthy = append(healthy, e)
+		}
+	}
+
+	return append(healthy, failed...), false
+}
+
+// pin returns opts pinning calls to the first available endpoint, unless
+// they already override the base URL. Pinned calls are not failed over.
+func (p *endpointPool) pin(opts []rest.CallOption) []rest.CallOption {
+	if rest.NewCallOptions(opts...).BaseURL != "" {
+		return opts
+	}
+
+	target := p.endpoints[0]
+	for _, e := range p.endpoints {
+		if !e.down.Load() {
+			target = e
+			break
+		}
+	}
+
+	return append(slices.Clip(opts), rest.CallBaseURL(target.baseURL))
+}
+
+// markDown takes the endpoint out of rotation and starts health checking it.
+func (p *endpointPool) markDown(e *endpoint) {
+	if !e.down.CompareAndSwap(false, true) {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.probe(e)
+	}()
+}
+
+// probe checks the endpoint health until it recovers or the pool is closed.
+func (p *endpointPool) probe(e *endpoint) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for e.down.Load() {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if p.isHealthy(e) {
+			e.down.Store(false)
+		}
+	}
+}
+
+func (p *endpointPool) isHealthy(e *endpoint) bool {
+	ctx, cancel := context.WithTimeout(rest.WithOperation(p.ctx, "CheckHealth"), p.interval)
+	defer cancel()
+
+	resp, err := rest.Do[HealthResponse](ctx, e.client, rest.Request{
+		Method:  http.MethodGet,
+		Path:    "/health",
+		Query:   nil,
+		Headers: nil,
+		Payload: nil,
+		Options: nil,
+	})
+	if err != nil {
+		return false
+	}
+
+	return resp.Status != HealthStatusFail
+}
+
+// close stops background health checks.
+func (p *endpointPool) close() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// isFailoverError reports whether the request may succeed on another endpoint.
+func (p *endpointPool) isFailoverError(ctx context.Context, req rest.Request, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	// the request was not sent
+	if errors.Is(err, rest.ErrCircuitOpen) || isDialError(err) {
+		return true
+	}
+
+	policy := rest.NewCallOptions(req.Options...).Retry
+	if policy == nil {
+		policy = p.retry
+	}
+	if !rest.IsIdempotent(req.Method) && !policy.RetryNonIdempotent {
+		// the server may have processed the request
+		return false
+	}
+
+	if rest.IsServerError(err) {
+		return true
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// isDialError reports whether err occurred while connecting to the server.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,427 @@
+package smsgateway_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func newEndpointServer(t *testing.T, user string, healthy *atomic.Bool, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			if !healthy.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"status":"fail"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"pass"}`))
+			return
+		}
+
+		hits.Add(1)
+		if u, _, _ := r.BasicAuth(); u != user {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if r.URL.Path == "/webhooks" {
+			_, _ = w.Write([]byte(`[{"id":"` + user + `"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"` + user + `","state":"Pending"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_Failover(t *testing.T) {
+	var primaryHealthy, backupHealthy atomic.Bool
+	var primaryHits, backupHits atomic.Int32
+	primaryHealthy.Store(false)
+	backupHealthy.Store(true)
+
+	primary := newEndpointServer(t, "primary", &primaryHealthy, &primaryHits)
+	backup := newEndpointServer(t, "backup", &backupHealthy, &backupHits)
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		Retry: rest.NoRetry(),
+		Endpoints: []smsgateway.Endpoint{
+			{BaseURL: primary.URL, User: "primary", Password: "secret"},
+			{BaseURL: backup.URL, User: "backup", Password: "secret"},
+		},
+		HealthCheckInterval: 10 * time.Millisecond,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	webhooks, err := client.ListWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("ListWebhooks() error = %v", err)
+	}
+	if got := servedBy(webhooks); got != "backup" {
+		t.Errorf("ListWebhooks() served by %q, want %q", got, "backup")
+	}
+
+	// failed endpoint is out of rotation
+	if webhooks, _ = client.ListWebhooks(ctx); servedBy(webhooks) != "backup" {
+		t.Errorf("ListWebhooks() served by %q, want %q", servedBy(webhooks), "backup")
+	}
+	if got := primaryHits.Load(); got != 1 {
+		t.Errorf("primary hits = %d, want 1", got)
+	}
+
+	// recovered endpoint is back in rotation
+	primaryHealthy.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for {
+		webhooks, err = client.ListWebhooks(ctx)
+		if err == nil && servedBy(webhooks) == "primary" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("primary endpoint did not recover, last webhooks %+v, error %v", webhooks, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// servedBy returns the user of the endpoint server that listed the webhooks.
+func servedBy(webhooks []smsgateway.Webhook) string {
+	if len(webhooks) == 0 {
+		return ""
+	}
+	return webhooks[0].ID
+}
+
+func TestClient_FailoverClientError(t *testing.T) {
+	var healthy atomic.Bool
+	var primaryHits, backupHits atomic.Int32
+	healthy.Store(true)
+
+	primary := newEndpointServer(t, "primary", &healthy, &primaryHits)
+	backup := newEndpointServer(t, "backup", &healthy, &backupHits)
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		Endpoints: []smsgateway.Endpoint{
+			{BaseURL: primary.URL, User: "wrong", Password: "secret"},
+			{BaseURL: backup.URL, User: "backup", Password: "secret"},
+		},
+	})
+	defer client.Close()
+
+	if _, err := client.ListWebhooks(context.Background()); !smsgateway.IsUnauthorized(err) {
+		t.Errorf("ListWebhooks() error = %v, want unauthorized", err)
+	}
+	if got := backupHits.Load(); got != 0 {
+		t.Errorf("backup hits = %d, want 0", got)
+	}
+}
+
+func TestClient_CheckHealth(t *testing.T) {
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	healthy.Store(true)
+
+	server := newEndpointServer(t, "user", &healthy, &hits)
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+	})
+
+	health, err := client.CheckHealth(context.Background())
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if health.Status != smsgateway.HealthStatusPass {
+		t.Errorf("CheckHealth() status = %q, want %q", health.Status, smsgateway.HealthStatusPass)
+	}
+}
+
+func TestClient_FailoverRequestID(t *testing.T) {
+	ids := make(chan string, 2)
+	handler := func(status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ids <- r.Header.Get(rest.RequestIDHeader)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}
+	primary := httptest.NewServer(handler(http.StatusBadGateway))
+	defer primary.Close()
+	backup := httptest.NewServer(handler(http.StatusOK))
+	defer backup.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		Retry: rest.NoRetry(),
+		Endpoints: []smsgateway.Endpoint{
+			{BaseURL: primary.URL, User: "user", Password: "secret"},
+			{BaseURL: backup.URL, User: "user", Password: "secret"},
+		},
+		HealthCheckInterval: time.Hour,
+	})
+	defer client.Close()
+
+	if _, err := client.ListWebhooks(context.Background()); err != nil {
+		t.Fatalf("ListWebhooks() error = %v", err)
+	}
+
+	first, second := <-ids, <-ids
+	if first == "" || first != second {
+		t.Errorf("request IDs = %q and %q, want equal", first, second)
+	}
+}
+
+func TestClient_CallBaseURL(t *testing.T) {
+	var primaryHealthy, backupHealthy atomic.Bool
+	var primaryHits, backupHits atomic.Int32
+	primaryHealthy.Store(false)
+	backupHealthy.Store(true)
+
+	primary := newEndpointServer(t, "primary", &primaryHealthy, &primaryHits)
+	backup := newEndpointServer(t, "backup", &backupHealthy, &backupHits)
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		Retry: rest.NoRetry(),
+		Endpoints: []smsgateway.Endpoint{
+			{BaseURL: primary.URL, User: "primary", Password: "secret"},
+			{BaseURL: backup.URL, User: "backup", Password: "secret"},
+		},
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	// pinned to the failing endpoint, no failover
+	if _, err := client.GetState(ctx, "123", rest.CallBaseURL(primary.URL)); !smsgateway.IsServerError(err) {
+		t.Errorf("GetState() error = %v, want server error", err)
+	}
+	if got := backupHits.Load(); got != 0 {
+		t.Errorf("backup hits = %d, want 0", got)
+	}
+
+	// the pinned failure does not take the endpoint out of rotation
+	if _, err := client.ListWebhooks(ctx); err != nil {
+		t.Fatalf("ListWebhooks() error = %v", err)
+	}
+	if got := primaryHits.Load(); got != 2 {
+		t.Errorf("primary hits = %d, want 2", got)
+	}
+
+	state, err := client.GetState(ctx, "123", rest.CallBaseURL(backup.URL))
+	if err != nil {
+		t.Fatalf("GetState() error = %v", err)
+	}
+	if state.ID != "backup" {
+		t.Errorf("GetState() served by %q, want %q", state.ID, "backup")
+	}
+}
+
+func TestClient_FailoverSend(t *testing.T) {
+	var primaryHealthy, backupHealthy atomic.Bool
+	var primaryHits, backupHits atomic.Int32
+	backupHealthy.Store(true)
+
+	primary := newEndpointServer(t, "primary", &primaryHealthy, &primaryHits)
+	backup := newEndpointServer(t, "backup", &backupHealthy, &backupHits)
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	message := smsgateway.Message{Message: "Hello", PhoneNumbers: []string{"+79990001234"}}
+
+	tests := []struct {
+		name           string
+		primaryURL     string
+		retry          *rest.RetryPolicy
+		want           string
+		wantErr        bool
+		wantBackupHits int32
+	}{
+		{
+			name:       "Server error",
+			primaryURL: primary.URL,
+			retry:      rest.NoRetry(),
+			wantErr:    true,
+		},
+		{
+			name:       "Server error with non-idempotent retries",
+			primaryURL: primary.URL,
+			retry: func() *rest.RetryPolicy {
+				policy := rest.NoRetry()
+				policy.RetryNonIdempotent = true
+				return policy
+			}(),
+			want:           "backup",
+			wantBackupHits: 1,
+		},
+		{
+			name:           "Connection refused",
+			primaryURL:     unreachable.URL,
+			retry:          rest.NoRetry(),
+			want:           "backup",
+			wantBackupHits: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primaryHits.Store(0)
+			backupHits.Store(0)
+
+			client := smsgateway.NewClient(smsgateway.Config{
+				Retry: tt.retry,
+				Endpoints: []smsgateway.Endpoint{
+					{BaseURL: tt.primaryURL, User: "primary", Password: "secret"},
+					{BaseURL: backup.URL, User: "backup", Password: "secret"},
+				},
+			})
+			defer client.Close()
+
+			state, err := client.Send(context.Background(), message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if state.ID != tt.want {
+				t.Errorf("Send() served by %q, want %q", state.ID, tt.want)
+			}
+			if got := backupHits.Load(); got != tt.wantBackupHits {
+				t.Errorf("backup hits = %d, want %d", got, tt.wantBackupHits)
+			}
+		})
+	}
+}
+
+func TestClient_CallTimeout(t *testing.T) {
+	slow := func() *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			// the server notices canceled requests once the body is read
+			_, _ = io.Copy(io.Discard, r.Body)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		Retry: rest.DefaultRetryPolicy(),
+		Endpoints: []smsgateway.Endpoint{
+			{BaseURL: slow().URL, User: "primary", Password: "secret"},
+			{BaseURL: slow().URL, User: "backup", Password: "secret"},
+		},
+	})
+	defer client.Close()
+
+	const timeout = 200 * time.Millisecond
+
+	// the timeout is shared by all endpoints and attempts
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{
+			name: "ListWebhooks",
+			call: func(ctx context.Context) error {
+				_, err := client.ListWebhooks(ctx, rest.CallTimeout(timeout))
+				return err
+			},
+		},
+		{
+			name: "SendIdempotent",
+			call: func(ctx context.Context) error {
+				_, err := client.SendIdempotent(ctx, smsgateway.Message{}, rest.CallTimeout(timeout))
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := tt.call(context.Background())
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+			}
+			if elapsed > timeout+100*time.Millisecond {
+				t.Errorf("elapsed = %v, want about %v", elapsed, timeout)
+			}
+		})
+	}
+}
+
+func TestClient_FailoverResource(t *testing.T) {
+	var backupHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		backupHits.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer backup.Close()
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		Retry: rest.NoRetry(),
+		Endpoints: []smsgateway.Endpoint{
+			{BaseURL: primary.URL, User: "user", Password: "secret"},
+			{BaseURL: backup.URL, User: "user", Password: "secret"},
+		},
+		HealthCheckInterval: time.Hour,
+	})
+	defer client.Close()
+
+	// the backup doesn't know resources of the primary
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{
+			name: "GetState",
+			call: func(ctx context.Context) error {
+				_, err := client.GetState(ctx, "123")
+				return err
+			},
+		},
+		{
+			name: "DeleteWebhook",
+			call: func(ctx context.Context) error {
+				return client.DeleteWebhook(ctx, "123")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(context.Background()); !smsgateway.IsServerError(err) {
+				t.Errorf("error = %v, want server error", err)
+			}
+			if got := backupHits.Load(); got != 0 {
+				t.Errorf("backup hits = %d, want 0", got)
+			}
+		})
+	}
+}
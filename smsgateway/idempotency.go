@@ -0,0 +1,143 @@
+package smsgateway
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+const (
+	messageIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_-"
+	messageIDLength   = 21
+)
+
+// ErrMessageStateUnknown is returned by SendIdempotent when it could not
+// determine whether the message was accepted. The send can be safely repeated
+// later with the same message ID.
+var ErrMessageStateUnknown = errors.New("message state is unknown")
+
+// GenerateMessageID returns a random 21-character URL-safe message ID in the
+// format generated by the server.
+func GenerateMessageID() string {
+	var b [messageIDLength]byte
+	_, _ = rand.Read(b[:])
+
+	for i := range b {
+		// the alphabet has 64 characters, so masking keeps the distribution uniform
+		b[i] = messageIDAlphabet[b[i]&63]
+	}
+
+	return string(b[:])
+}
+
+// SendIdempotent sends an SMS message without the risk of sending it twice.
+//
+// The message ID is generated if not set. If an attempt fails in a way that
+// leaves it unknown whether the server accepted the message, such as a timeout
+// or a 5xx response, the message state is looked up by ID and the message is
+// only sent again if it doesn't exist. All requests go to the same endpoint.
+// Attempts and delays follow the retry policy of the call or the client.
+// The options apply to every request, the timeout to the whole call.
+func (c *Client) SendIdempotent(ctx context.Context, message Message, opts ...rest.CallOption) (MessageState, error) {
+	ctx, cancel := withCallTimeout(ctx, opts)
+	defer cancel()
+
+	if message.ID == "" {
+		message.ID = GenerateMessageID()
+	}
+
+	// another endpoint may not know the message
+	opts = c.endpoints.pin(opts)
+
+	policy := rest.NewCallOptions(opts...).Retry
+	if policy == nil {
+		policy = c.retry
+	}
+
+	var (
+		lastErr error
+		// whether a failed attempt may have been accepted
+		pending = false
+		// whether the state was looked up after the last ambiguous failure
+		checked = false
+	)
+	for attempt := 1; ; attempt++ {
+		if pending {
+			state, err := c.GetState(ctx, message.ID, opts...)
+			checked = true
+			switch {
+			case err == nil:
+				return state, nil
+			case IsNotFound(err):
+				pending = false
+			default:
+				lastErr = err
+			}
+		}
+
+		if !pending {
+			state, err := c.Send(ctx, message, opts...)
+			if err == nil {
+				return state, nil
+			}
+			lastErr = err
+
+			switch {
+			case IsConflict(err), isAmbiguous(err):
+				// a message with the ID already exists or may have been accepted
+				pending = true
+				checked = false
+			case IsRateLimited(err):
+			default:
+				return MessageState{}, err
+			}
+		}
+
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		if err := rest.Sleep(ctx, policy.Delay(attempt, lastErr)); err != nil {
+			break
+		}
+	}
+
+	if pending && !checked {
+		// the state is looked up even if the message can't be sent again
+		state, err := c.GetState(ctx, message.ID, opts...)
+		switch {
+		case err == nil:
+			return state, nil
+		case IsNotFound(err):
+			return MessageState{}, lastErr
+		default:
+			lastErr = err
+		}
+	}
+
+	if pending {
+		return MessageState{}, fmt.Errorf("%w: message %s: %w", ErrMessageStateUnknown, message.ID, lastErr)
+	}
+
+	return MessageState{}, lastErr
+}
+
+// isAmbiguous reports whether a request that failed with err may have been
+// processed by the server.
+func isAmbiguous(err error) bool {
+	if errors.Is(err, rest.ErrCircuitOpen) {
+		return false
+	}
+
+	if rest.IsServerError(err) {
+		return true
+	}
+
+	// transport errors, including timeouts of the request context
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,232 @@
+package smsgateway_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestGenerateMessageID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`)
+
+	seen := map[string]bool{}
+	for range 100 {
+		id := smsgateway.GenerateMessageID()
+		if !pattern.MatchString(id) {
+			t.Fatalf("GenerateMessageID() = %q, want 21 URL-safe characters", id)
+		}
+		if seen[id] {
+			t.Fatalf("GenerateMessageID() returned %q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+// gatewayServer stores sent messages. The send handler decides whether a
+// POST is accepted and which status is returned.
+type gatewayServer struct {
+	mu       sync.Mutex
+	messages map[string]bool
+	posts    int
+	gets     int
+	send     func(post int) (accept bool, status int)
+	getError int
+}
+
+func (s *gatewayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodGet {
+		s.gets++
+		if s.getError != 0 {
+			w.WriteHeader(s.getError)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/message/")
+		if !s.messages[id] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"` + id + `","state":"Pending"}`))
+		return
+	}
+
+	var message smsgateway.Message
+	_ = json.NewDecoder(r.Body).Decode(&message)
+
+	s.posts++
+	if s.messages[message.ID] {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	accept, status := s.send(s.posts)
+	if accept {
+		s.messages[message.ID] = true
+	}
+	w.WriteHeader(status)
+	if status < http.StatusBadRequest {
+		_, _ = w.Write([]byte(`{"id":"` + message.ID + `","state":"Pending"}`))
+	}
+}
+
+func TestClient_SendIdempotent(t *testing.T) {
+	tests := []struct {
+		name      string
+		send      func(post int) (bool, int)
+		getError  int
+		opts      []rest.CallOption
+		wantPosts int
+		wantGets  int
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			send:      func(int) (bool, int) { return true, http.StatusAccepted },
+			wantPosts: 1,
+		},
+		{
+			name: "Accepted with lost response",
+			send: func(post int) (bool, int) {
+				if post == 1 {
+					return true, http.StatusBadGateway
+				}
+				return true, http.StatusAccepted
+			},
+			wantPosts: 1,
+			wantGets:  1,
+		},
+		{
+			name: "Not accepted",
+			send: func(post int) (bool, int) {
+				if post == 1 {
+					return false, http.StatusServiceUnavailable
+				}
+				return true, http.StatusAccepted
+			},
+			wantPosts: 2,
+			wantGets:  1,
+		},
+		{
+			name:      "Rejected",
+			send:      func(int) (bool, int) { return false, http.StatusBadRequest },
+			wantPosts: 1,
+			wantErr:   rest.ErrAPIError,
+		},
+		{
+			name:      "Unknown",
+			send:      func(int) (bool, int) { return false, http.StatusBadGateway },
+			getError:  http.StatusInternalServerError,
+			wantPosts: 1,
+			wantGets:  2,
+			wantErr:   smsgateway.ErrMessageStateUnknown,
+		},
+		{
+			name:      "Accepted without retries",
+			send:      func(int) (bool, int) { return true, http.StatusBadGateway },
+			opts:      []rest.CallOption{rest.CallRetryPolicy(rest.NoRetry())},
+			wantPosts: 1,
+			wantGets:  1,
+		},
+		{
+			name:      "Not accepted without retries",
+			send:      func(int) (bool, int) { return false, http.StatusBadGateway },
+			opts:      []rest.CallOption{rest.CallRetryPolicy(rest.NoRetry())},
+			wantPosts: 1,
+			wantGets:  1,
+			wantErr:   rest.ErrAPIError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayServer{
+				messages: map[string]bool{},
+				send:     tt.send,
+				getError: tt.getError,
+			}
+			server := httptest.NewServer(gateway)
+			defer server.Close()
+
+			retry := rest.DefaultRetryPolicy()
+			retry.InitialBackoff = time.Millisecond
+			client := smsgateway.NewClient(smsgateway.Config{
+				BaseURL: server.URL,
+				Retry:   retry,
+			})
+
+			state, err := client.SendIdempotent(context.Background(), smsgateway.Message{
+				Message:      "Hello",
+				PhoneNumbers: []string{"+79990001234"},
+			}, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SendIdempotent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(state.ID) != 21 {
+				t.Errorf("SendIdempotent() ID = %q, want generated ID", state.ID)
+			}
+			if gateway.posts != tt.wantPosts {
+				t.Errorf("posts = %d, want %d", gateway.posts, tt.wantPosts)
+			}
+			if gateway.gets != tt.wantGets {
+				t.Errorf("gets = %d, want %d", gateway.gets, tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestClient_SendIdempotentEndpoints(t *testing.T) {
+	primary := &gatewayServer{
+		messages: map[string]bool{},
+		send: func(post int) (bool, int) {
+			if post == 1 {
+				return true, http.StatusBadGateway
+			}
+			return true, http.StatusAccepted
+		},
+	}
+	backup := &gatewayServer{
+		messages: map[string]bool{},
+		send:     func(int) (bool, int) { return true, http.StatusAccepted },
+	}
+	primaryServer, backupServer := httptest.NewServer(primary), httptest.NewServer(backup)
+	defer primaryServer.Close()
+	defer backupServer.Close()
+
+	retry := rest.DefaultRetryPolicy()
+	retry.InitialBackoff = time.Millisecond
+	retry.RetryNonIdempotent = true // would fail over a plain Send
+	client := smsgateway.NewClient(smsgateway.Config{
+		Retry: retry,
+		Endpoints: []smsgateway.Endpoint{
+			{BaseURL: primaryServer.URL, User: "user", Password: "secret"},
+			{BaseURL: backupServer.URL, User: "user", Password: "secret"},
+		},
+	})
+	defer client.Close()
+
+	if _, err := client.SendIdempotent(context.Background(), smsgateway.Message{
+		Message:      "Hello",
+		PhoneNumbers: []string{"+79990001234"},
+	}, rest.CallRetryPolicy(rest.NoRetry())); err != nil {
+		t.Fatalf("SendIdempotent() error = %v", err)
+	}
+	if primary.posts != 1 || primary.gets != 1 {
+		t.Errorf("primary posts = %d, gets = %d, want 1 and 1", primary.posts, primary.gets)
+	}
+	if backup.posts != 0 || backup.gets != 0 {
+		t.Errorf("backup posts = %d, gets = %d, want 0 and 0", backup.posts, backup.gets)
+	}
+}
@@ -0,0 +1,30 @@
+package smsgateway
+
+import "github.com/android-sms-gateway/client-go/rest"
+
+// Redaction configures masking of personal data in logs and API errors.
+// The Authorization header is always masked.
+type Redaction struct {
+	PhoneNumbers  bool // Mask recipient phone numbers
+	MessageBodies bool // Mask message content of requests
+	ErrorBodies   bool // Replace API error bodies with a placeholder
+}
+
+func (r Redaction) rest() rest.Redaction {
+	fields := []string{}
+	requestFields := []string{}
+	if r.PhoneNumbers {
+		fields = append(fields, "phoneNumbers", "phoneNumber")
+	}
+	if r.MessageBodies {
+		fields = append(fields, "textMessage", "dataMessage")
+		// the message of API errors is kept
+		requestFields = append(requestFields, "message")
+	}
+
+	return rest.Redaction{
+		Fields:        fields,
+		RequestFields: requestFields,
+		ErrorBody:     r.ErrorBodies,
+	}
+}
@@ -0,0 +1,84 @@
+package smsgateway_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestClient_LoggingRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"id":"123","state":"Pending","recipients":[{"phoneNumber":"+19162255887","state":"Pending"}]}`))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL:   server.URL,
+		User:      "user",
+		Password:  "password",
+		Logger:    slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogBodies: true,
+		Redact: smsgateway.Redaction{
+			PhoneNumbers:  true,
+			MessageBodies: true,
+			ErrorBodies:   true,
+		},
+	})
+
+	_, err := client.Send(context.Background(), smsgateway.Message{
+		Message:      "Your code is 424242",
+		PhoneNumbers: []string{"+19162255887"},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"+19162255887", "424242", "dXNlcjpwYXNzd29yZA=="} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, logs)
+		}
+	}
+	if !strings.Contains(logs, `"operation":"Send"`) {
+		t.Errorf("logs don't contain operation:\n%s", logs)
+	}
+}
+
+func TestClient_RedactionErrorMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":1,"message":"invalid phone number"}`))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL:   server.URL,
+		User:      "user",
+		Password:  "password",
+		Logger:    slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogBodies: true,
+		Redact:    smsgateway.Redaction{MessageBodies: true},
+	})
+
+	_, err := client.Send(context.Background(), smsgateway.Message{
+		Message:      "Your code is 424242",
+		PhoneNumbers: []string{"+19162255887"},
+	})
+
+	// the message of the request is masked, the message of the error is not
+	if err == nil || !strings.Contains(err.Error(), "invalid phone number") {
+		t.Errorf("Send() error = %v, want error message of the server", err)
+	}
+	if logs := buf.String(); strings.Contains(logs, "424242") {
+		t.Errorf("logs contain message:\n%s", logs)
+	}
+}
@@ -0,0 +1,123 @@
+package smsgateway
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+// Option configures a Client created with New.
+type Option func(*Config)
+
+// New creates a new instance of the API Client from options.
+//
+// Settings shared with the other API clients, such as the base URL, retry
+// policy or logger, are set with WithOptions.
+func New(options ...Option) *Client {
+	var config Config
+	for _, option := range options {
+		option(&config)
+	}
+
+	return NewClient(config)
+}
+
+// WithOptions applies options shared with the other API clients, such as
+// rest.WithBaseURL or rest.WithLogger.
+func WithOptions(options ...rest.Option) Option {
+	return func(c *Config) {
+		config := c.rest()
+		config.Apply(options...)
+
+		c.Client = config.Client
+		c.BaseURL = config.BaseURL
+		c.Retry = config.Retry
+		c.Transport = config.Transport
+		c.Authenticator = config.Authenticator
+		c.TLS = config.TLS
+		c.CircuitBreaker = config.CircuitBreaker
+		c.Cache = config.Cache
+		c.Middlewares = config.Middlewares
+		c.Compression = config.Compression
+		c.CoalesceRequests = config.CoalesceRequests
+		c.MaxResponseSize = config.MaxResponseSize
+		c.Logger = config.Logger
+		c.LogBodies = config.LogBodies
+	}
+}
+
+// WithBasicAuth sets the username and password.
+func WithBasicAuth(user, password string) Option {
+	return func(c *Config) {
+		c.User = user
+		c.Password = password
+	}
+}
+
+// WithCredentials sets a source of rotating credentials, consulted for every request.
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(c *Config) {
+		c.Credentials = provider
+	}
+}
+
+// WithEndpoints sets an ordered list of servers to fail over between.
+func WithEndpoints(endpoints ...Endpoint) Option {
+	return func(c *Config) {
+		c.Endpoints = endpoints
+	}
+}
+
+// WithHealthCheckInterval sets the interval of health checks of failed endpoints.
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.HealthCheckInterval = interval
+	}
+}
+
+// WithLimits enables client-side rate limiting.
+func WithLimits(limits *RateLimits) Option {
+	return func(c *Config) {
+		c.Limits = limits
+	}
+}
+
+// WithRedaction masks personal data in logs and API errors.
+func WithRedaction(redact Redaction) Option {
+	return func(c *Config) {
+		c.Redact = redact
+	}
+}
+
+// rest returns the settings shared with the other API clients.
+func (c Config) rest() rest.Config {
+	return rest.Config{
+		Client:  c.Client,
+		BaseURL: c.BaseURL,
+		Retry:   c.Retry,
+
+		Transport: c.Transport,
+
+		Authenticator: c.Authenticator,
+
+		TLS: c.TLS,
+
+		CircuitBreaker: c.CircuitBreaker,
+		Cache:          c.Cache,
+		// delivery states and health checks must not be stale
+		CachePaths: []string{"/device", "/webhooks"},
+
+		Middlewares: c.Middlewares,
+
+		Compression: c.Compression,
+
+		CoalesceRequests: c.CoalesceRequests,
+
+		MaxResponseSize:  c.MaxResponseSize,
+		MaxErrorBodySize: 0,
+
+		Logger:    c.Logger,
+		LogBodies: c.LogBodies,
+		Redact:    c.Redact.rest(),
+	}
+}
@@ -0,0 +1,53 @@
+package smsgateway_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestNew(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if user, password, _ := r.BasicAuth(); user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var calls atomic.Int32
+	middleware := func(next rest.RoundTripFunc) rest.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			calls.Add(1)
+			return next(req)
+		}
+	}
+
+	client := smsgateway.New(
+		smsgateway.WithBasicAuth("user", "secret"),
+		smsgateway.WithOptions(
+			rest.WithBaseURL(server.URL),
+			rest.WithRetryPolicy(rest.NoRetry()),
+		),
+		smsgateway.WithOptions(rest.WithMiddleware(middleware)),
+	)
+	defer client.Close()
+
+	if _, err := client.GetState(context.Background(), "123"); !smsgateway.IsServerError(err) {
+		t.Errorf("GetState() error = %v, want server error", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits = %d, want 1", got)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("middleware calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,65 @@
+package smsgateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/android-sms-gateway/client-go/rest"
+)
+
+// Route identifies an API endpoint by its HTTP method and path pattern.
+type Route string
+
+const (
+	RouteSend            Route = "POST /message"
+	RouteGetState        Route = "GET /message/{id}"
+	RouteListWebhooks    Route = "GET /webhooks"
+	RouteRegisterWebhook Route = "POST /webhooks"
+	RouteDeleteWebhook   Route = "DELETE /webhooks/{id}"
+	RouteListDevices     Route = "GET /device"
+	RouteGetLogs         Route = "GET /logs"
+)
+
+// RateLimits configures client-side rate limiting.
+//
+// Limiters may be shared between routes to give them a common budget.
+type RateLimits struct {
+	// Default limiter for routes without a dedicated one, no limit if nil.
+	Default rest.RateLimiter
+	// Per-route limiters.
+	Routes map[Route]rest.RateLimiter
+	// Optional limiter for messages with priority of at least `PriorityBypassThreshold`,
+	// used instead of the route limiter so urgent messages aren't starved by bulk traffic.
+	HighPriority rest.RateLimiter
+}
+
+// limiter returns the limiter for the route and message priority.
+func (l *RateLimits) limiter(route Route, priority MessagePriority) rest.RateLimiter {
+	if l == nil {
+		return nil
+	}
+
+	if priority >= PriorityBypassThreshold && l.HighPriority != nil {
+		return l.HighPriority
+	}
+
+	if limiter, ok := l.Routes[route]; ok {
+		return limiter
+	}
+
+	return l.Default
+}
+
+// wait blocks until the limiter for the route allows a request or ctx is done.
+func (l *RateLimits) wait(ctx context.Context, route Route, priority MessagePriority) error {
+	limiter := l.limiter(route, priority)
+	if limiter == nil {
+		return nil
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package smsgateway_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+type countingLimiter struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (l *countingLimiter) Wait(_ context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	return l.err
+}
+
+func TestClient_RateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/webhooks" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"123","state":"Pending"}`))
+	}))
+	defer server.Close()
+
+	defaultLimiter := &countingLimiter{}
+	sendLimiter := &countingLimiter{}
+	priorityLimiter := &countingLimiter{}
+
+	client := smsgateway.NewClient(smsgateway.Config{
+		BaseURL: server.URL,
+		Limits: &smsgateway.RateLimits{
+			Default: defaultLimiter,
+			Routes: map[smsgateway.Route]rest.RateLimiter{
+				smsgateway.RouteSend: sendLimiter,
+			},
+			HighPriority: priorityLimiter,
+		},
+	})
+
+	ctx := context.Background()
+	if _, err := client.Send(ctx, smsgateway.Message{Message: "bulk", PhoneNumbers: []string{"+1"}}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if _, err := client.Send(ctx, smsgateway.Message{
+		Message:      "otp",
+		PhoneNumbers: []string{"+1"},
+		Priority:     smsgateway.PriorityBypassThreshold,
+	}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if _, err := client.GetState(ctx, "123"); err != nil {
+		t.Fatalf("GetState() error = %v", err)
+	}
+	if _, err := client.ListWebhooks(ctx); err != nil {
+		t.Fatalf("ListWebhooks() error = %v", err)
+	}
+
+	if sendLimiter.calls != 1 {
+		t.Errorf("send limiter calls = %d, want 1", sendLimiter.calls)
+	}
+	if priorityLimiter.calls != 1 {
+		t.Errorf("priority limiter calls = %d, want 1", priorityLimiter.calls)
+	}
+	if defaultLimiter.calls != 2 {
+		t.Errorf("default limiter calls = %d, want 2", defaultLimiter.calls)
+	}
+
+	defaultLimiter.err = context.Canceled
+	if _, err := client.ListDevices(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("ListDevices() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/android-sms-gateway/client-go/ca"
+	"github.com/android-sms-gateway/client-go/rest"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CAClient is an instrumented ca.Client.
+type CAClient struct {
+	*ca.Client
+
+	inst *Instrumentation
+}
+
+// WrapCA returns an instrumented client that creates a span for every API call.
+func (i *Instrumentation) WrapCA(client *ca.Client) *CAClient {
+	return &CAClient{
+		Client: client,
+		inst:   i,
+	}
+}
+
+// PostCSR posts a Certificate Signing Request (CSR) to the Certificate Authority (CA) service.
+func (c *CAClient) PostCSR(ctx context.Context, request ca.PostCSRRequest, opts ...rest.CallOption) (ca.PostCSRResponse, error) {
+	return observe(ctx, c.inst, "ca.PostCSR", nil,
+		func(ctx context.Context) (ca.PostCSRResponse, error) {
+			return c.Client.PostCSR(ctx, request, opts...)
+		},
+		csrAttrs,
+	)
+}
+
+// GetCSRStatus retrieves the status of a Certificate Signing Request (CSR).
+func (c *CAClient) GetCSRStatus(ctx context.Context, requestID string, opts ...rest.CallOption) (ca.GetCSRStatusResponse, error) {
+	return observe(ctx, c.inst, "ca.GetCSRStatus", []attribute.KeyValue{AttrCSRRequestID.String(requestID)},
+		func(ctx context.Context) (ca.GetCSRStatusResponse, error) {
+			return c.Client.GetCSRStatus(ctx, requestID, opts...)
+		},
+		csrAttrs,
+	)
+}
+
+func csrAttrs(resp ca.PostCSRResponse) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttrCSRRequestID.String(resp.RequestID),
+		AttrCSRStatus.String(string(resp.Status)),
+	}
+}
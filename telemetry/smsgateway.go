@@ -0,0 +1,156 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SMSGatewayClient is an instrumented smsgateway.Client.
+type SMSGatewayClient struct {
+	*smsgateway.Client
+
+	inst *Instrumentation
+}
+
+// WrapSMSGateway returns an instrumented client that creates a span for every API call.
+func (i *Instrumentation) WrapSMSGateway(client *smsgateway.Client) *SMSGatewayClient {
+	return &SMSGatewayClient{
+		Client: client,
+		inst:   i,
+	}
+}
+
+// Send sends an SMS message.
+func (c *SMSGatewayClient) Send(ctx context.Context, message smsgateway.Message, opts ...rest.CallOption) (smsgateway.MessageState, error) {
+	attrs := []attribute.KeyValue{AttrRecipientCount.Int(len(message.PhoneNumbers))}
+	if message.ID != "" {
+		attrs = append(attrs, AttrMessageID.String(message.ID))
+	}
+
+	return observe(ctx, c.inst, "smsgateway.Send", attrs,
+		func(ctx context.Context) (smsgateway.MessageState, error) {
+			return c.Client.Send(ctx, message, opts...)
+		},
+		messageStateAttrs,
+	)
+}
+
+// SendIdempotent sends an SMS message without the risk of sending it twice.
+func (c *SMSGatewayClient) SendIdempotent(ctx context.Context, message smsgateway.Message, opts ...rest.CallOption) (smsgateway.MessageState, error) {
+	if message.ID == "" {
+		// generated here to be recorded in the span
+		message.ID = smsgateway.GenerateMessageID()
+	}
+
+	attrs := []attribute.KeyValue{
+		AttrRecipientCount.Int(len(message.PhoneNumbers)),
+		AttrMessageID.String(message.ID),
+	}
+
+	return observe(ctx, c.inst, "smsgateway.SendIdempotent", attrs,
+		func(ctx context.Context) (smsgateway.MessageState, error) {
+			return c.Client.SendIdempotent(ctx, message, opts...)
+		},
+		messageStateAttrs,
+	)
+}
+
+// GetState gets the state of an SMS message by ID.
+func (c *SMSGatewayClient) GetState(ctx context.Context, messageID string, opts ...rest.CallOption) (smsgateway.MessageState, error) {
+	return observe(ctx, c.inst, "smsgateway.GetState", []attribute.KeyValue{AttrMessageID.String(messageID)},
+		func(ctx context.Context) (smsgateway.MessageState, error) {
+			return c.Client.GetState(ctx, messageID, opts...)
+		},
+		messageStateAttrs,
+	)
+}
+
+// ListWebhooks retrieves all registered webhooks.
+func (c *SMSGatewayClient) ListWebhooks(ctx context.Context, opts ...rest.CallOption) ([]smsgateway.Webhook, error) {
+	return observe(ctx, c.inst, "smsgateway.ListWebhooks", nil,
+		func(ctx context.Context) ([]smsgateway.Webhook, error) {
+			return c.Client.ListWebhooks(ctx, opts...)
+		},
+		nil,
+	)
+}
+
+// RegisterWebhook registers a new webhook.
+func (c *SMSGatewayClient) RegisterWebhook(ctx context.Context, webhook smsgateway.Webhook, opts ...rest.CallOption) (smsgateway.Webhook, error) {
+	return observe(ctx, c.inst, "smsgateway.RegisterWebhook", nil,
+		func(ctx context.Context) (smsgateway.Webhook, error) {
+			return c.Client.RegisterWebhook(ctx, webhook, opts...)
+		},
+		func(w smsgateway.Webhook) []attribute.KeyValue {
+			return []attribute.KeyValue{AttrWebhookID.String(w.ID)}
+		},
+	)
+}
+
+// DeleteWebhook removes a webhook with the specified ID.
+func (c *SMSGatewayClient) DeleteWebhook(ctx context.Context, webhookID string, opts ...rest.CallOption) error {
+	_, err := observe(ctx, c.inst, "smsgateway.DeleteWebhook", []attribute.KeyValue{AttrWebhookID.String(webhookID)},
+		noResult(func(ctx context.Context) error {
+			return c.Client.DeleteWebhook(ctx, webhookID, opts...)
+		}),
+		nil,
+	)
+
+	return err
+}
+
+// ListDevices retrieves all registered devices in the account.
+func (c *SMSGatewayClient) ListDevices(ctx context.Context, opts ...rest.CallOption) ([]smsgateway.Device, error) {
+	return observe(ctx, c.inst, "smsgateway.ListDevices", nil,
+		func(ctx context.Context) ([]smsgateway.Device, error) {
+			return c.Client.ListDevices(ctx, opts...)
+		},
+		nil,
+	)
+}
+
+// GetLogs retrieves device log entries created between from and to.
+func (c *SMSGatewayClient) GetLogs(ctx context.Context, from, to time.Time, opts ...rest.CallOption) ([]smsgateway.LogEntry, error) {
+	return observe(ctx, c.inst, "smsgateway.GetLogs", nil,
+		func(ctx context.Context) ([]smsgateway.LogEntry, error) {
+			return c.Client.GetLogs(ctx, from, to, opts...)
+		},
+		nil,
+	)
+}
+
+// StreamWebhooks calls fn for every registered webhook as it is received.
+func (c *SMSGatewayClient) StreamWebhooks(ctx context.Context, fn func(smsgateway.Webhook) error, opts ...rest.CallOption) error {
+	_, err := observe(ctx, c.inst, "smsgateway.StreamWebhooks", nil,
+		noResult(func(ctx context.Context) error {
+			return c.Client.StreamWebhooks(ctx, fn, opts...)
+		}),
+		nil,
+	)
+
+	return err
+}
+
+// StreamDevices calls fn for every registered device as it is received.
+func (c *SMSGatewayClient) StreamDevices(ctx context.Context, fn func(smsgateway.Device) error, opts ...rest.CallOption) error {
+	_, err := observe(ctx, c.inst, "smsgateway.StreamDevices", nil,
+		noResult(func(ctx context.Context) error {
+			return c.Client.StreamDevices(ctx, fn, opts...)
+		}),
+		nil,
+	)
+
+	return err
+}
+
+func messageStateAttrs(state smsgateway.MessageState) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttrMessageID.String(state.ID),
+		AttrMessageState.String(string(state.State)),
+		AttrRecipientCount.Int(len(state.Recipients)),
+	}
+}
@@ -0,0 +1,187 @@
+// Package telemetry provides OpenTelemetry tracing and metrics for the
+// smsgateway and ca API clients.
+//
+// Wrap a client to get a span per API call and install the Middleware on the
+// wrapped client to propagate the W3C trace context to the server:
+//
+//	inst, err := telemetry.New()
+//	...
+//	client := inst.WrapSMSGateway(smsgateway.NewClient(smsgateway.Config{
+//		Middlewares: []rest.Middleware{inst.Middleware()},
+//	}))
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/rest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/android-sms-gateway/client-go/telemetry"
+
+const (
+	AttrOperation      = attribute.Key("operation")
+	AttrHTTPStatusCode = attribute.Key("http.response.status_code")
+	AttrMessageID      = attribute.Key("sms.message.id")
+	AttrRecipientCount = attribute.Key("sms.message.recipients")
+	AttrMessageState   = attribute.Key("sms.message.state")
+	AttrWebhookID      = attribute.Key("sms.webhook.id")
+	AttrCSRRequestID   = attribute.Key("ca.csr.request_id")
+	AttrCSRStatus      = attribute.Key("ca.csr.status")
+)
+
+// Option configures Instrumentation.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider, defaults to the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider, defaults to the global one.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to inject the trace context into
+// outgoing requests, defaults to W3C Trace Context.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Instrumentation creates spans and records metrics for API client calls.
+type Instrumentation struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// New creates a new Instrumentation.
+func New(options ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     propagation.TraceContext{},
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram(
+		"smsgateway.client.duration",
+		metric.WithDescription("Duration of API client calls, including retries."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create duration histogram: %w", err)
+	}
+
+	errorCounter, err := meter.Int64Counter(
+		"smsgateway.client.errors",
+		metric.WithDescription("Number of failed API client calls."),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create errors counter: %w", err)
+	}
+
+	return &Instrumentation{
+		tracer:     cfg.tracerProvider.Tracer(instrumentationName),
+		propagator: cfg.propagator,
+
+		duration: duration,
+		errors:   errorCounter,
+	}, nil
+}
+
+// Middleware returns a middleware that injects the trace context into
+// outgoing requests and records the HTTP status code on the current span.
+func (i *Instrumentation) Middleware() rest.Middleware {
+	return func(next rest.RoundTripFunc) rest.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			i.propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(req)
+			if resp != nil {
+				trace.SpanFromContext(req.Context()).SetAttributes(AttrHTTPStatusCode.Int(resp.StatusCode))
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// observe runs call inside a span named after the operation and records its
+// duration and failure.
+func observe[T any](
+	ctx context.Context,
+	i *Instrumentation,
+	operation string,
+	attrs []attribute.KeyValue,
+	call func(context.Context) (T, error),
+	result func(T) []attribute.KeyValue,
+) (T, error) {
+	start := time.Now()
+
+	ctx, span := i.tracer.Start(
+		ctx,
+		operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	res, err := call(ctx)
+
+	metricAttrs := metric.WithAttributes(AttrOperation.String(operation))
+	i.duration.Record(ctx, time.Since(start).Seconds(), metricAttrs)
+
+	if err != nil {
+		if apiErr, ok := rest.AsAPIError(err); ok {
+			span.SetAttributes(AttrHTTPStatusCode.Int(apiErr.StatusCode))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		i.errors.Add(ctx, 1, metricAttrs)
+
+		return res, err
+	}
+
+	if result != nil {
+		span.SetAttributes(result(res)...)
+	}
+
+	return res, nil
+}
+
+// noResult adapts a call without a result to observe.
+func noResult(call func(context.Context) error) func(context.Context) (struct{}, error) {
+	return func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	}
+}
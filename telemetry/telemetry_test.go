@@ -0,0 +1,160 @@
+package telemetry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/ca"
+	"github.com/android-sms-gateway/client-go/rest"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/client-go/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newInstrumentation(t *testing.T) (*telemetry.Instrumentation, *tracetest.InMemoryExporter, *sdkmetric.ManualReader, trace.Tracer) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	inst, err := telemetry.New(
+		telemetry.WithTracerProvider(tracerProvider),
+		telemetry.WithMeterProvider(meterProvider),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return inst, exporter, reader, tracerProvider.Tracer("test")
+}
+
+func attrValue(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestSMSGatewayClient_Send(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"id":"abc","state":"Pending","recipients":[{"phoneNumber":"+1","state":"Pending"},{"phoneNumber":"+2","state":"Pending"}]}`))
+	}))
+	defer server.Close()
+
+	inst, exporter, reader, tracer := newInstrumentation(t)
+	client := inst.WrapSMSGateway(smsgateway.NewClient(smsgateway.Config{
+		BaseURL:     server.URL,
+		Middlewares: []rest.Middleware{inst.Middleware()},
+	}))
+
+	ctx, parent := tracer.Start(context.Background(), "incoming request")
+	_, err := client.Send(ctx, smsgateway.Message{
+		Message:      "Hello",
+		PhoneNumbers: []string{"+1", "+2"},
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "smsgateway.Send" {
+		t.Errorf("span name = %q, want %q", span.Name, "smsgateway.Send")
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("span is not a child of the incoming request span")
+	}
+
+	wantAttrs := map[attribute.Key]attribute.Value{
+		telemetry.AttrMessageID:      attribute.StringValue("abc"),
+		telemetry.AttrMessageState:   attribute.StringValue("Pending"),
+		telemetry.AttrRecipientCount: attribute.IntValue(2),
+		telemetry.AttrHTTPStatusCode: attribute.IntValue(http.StatusAccepted),
+	}
+	for key, want := range wantAttrs {
+		if got, ok := attrValue(span.Attributes, key); !ok || got != want {
+			t.Errorf("attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+
+	wantTraceparent := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if traceparent != wantTraceparent {
+		t.Errorf("traceparent = %q, want %q", traceparent, wantTraceparent)
+	}
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(metrics.ScopeMetrics) != 1 || len(metrics.ScopeMetrics[0].Metrics) != 1 {
+		t.Fatalf("expected only the duration metric, got %+v", metrics.ScopeMetrics)
+	}
+	if name := metrics.ScopeMetrics[0].Metrics[0].Name; name != "smsgateway.client.duration" {
+		t.Errorf("metric name = %q, want %q", name, "smsgateway.client.duration")
+	}
+}
+
+func TestCAClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	inst, exporter, reader, _ := newInstrumentation(t)
+	client := inst.WrapCA(ca.NewClient(
+		ca.WithBaseURL(server.URL),
+		ca.WithMiddleware(inst.Middleware()),
+	))
+
+	if _, err := client.GetCSRStatus(context.Background(), "123"); err == nil {
+		t.Fatal("GetCSRStatus() error = nil, want error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name != "ca.GetCSRStatus" {
+		t.Errorf("span name = %q, want %q", spans[0].Name, "ca.GetCSRStatus")
+	}
+	if got, _ := attrValue(spans[0].Attributes, telemetry.AttrHTTPStatusCode); got.AsInt64() != http.StatusNotFound {
+		t.Errorf("status attribute = %v, want %d", got.Emit(), http.StatusNotFound)
+	}
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	var errorsCount int64
+	for _, m := range metrics.ScopeMetrics[0].Metrics {
+		if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "smsgateway.client.errors" {
+			for _, dp := range sum.DataPoints {
+				errorsCount += dp.Value
+			}
+		}
+	}
+	if errorsCount != 1 {
+		t.Errorf("errors counter = %d, want 1", errorsCount)
+	}
+}